@@ -0,0 +1,107 @@
+package lob
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Environment is a Lob environment. Requests made with test keys are never printed or mailed;
+// requests made with live keys are, and checks drawn on live bank accounts move real money.
+type Environment string
+
+// Lob environments.
+const (
+	EnvironmentTest Environment = "test"
+	EnvironmentLive Environment = "live"
+)
+
+// Errors returned when the client has no usable API key for a request.
+var (
+	ErrNoAPIKey               = errors.New("no Lob API key configured")
+	ErrUnknownAccount         = errors.New("unknown Lob account")
+	ErrLiveKeyNotAllowed      = errors.New("live Lob API key used without AllowLive")
+	ErrKeyEnvironmentMismatch = errors.New("Lob API key does not belong to the client's environment")
+)
+
+// KeyEnvironment reports which environment an API key belongs to. Lob's test keys start with
+// "test"; any other key is treated as live.
+func KeyEnvironment(key string) Environment {
+	if strings.HasPrefix(key, "test") {
+		return EnvironmentTest
+	}
+	return EnvironmentLive
+}
+
+// Account is a Lob account along with its API keys for each environment.
+type Account struct {
+	TestKey string `json:"test_key"`
+	LiveKey string `json:"live_key"`
+}
+
+// Key returns the account's API key for env.
+func (a Account) Key(env Environment) string {
+	if env == EnvironmentLive {
+		return a.LiveKey
+	}
+	return a.TestKey
+}
+
+// WithAccount returns a copy of the client that sends requests on behalf of the named entry in
+// Accounts, using its key for the client's environment. Each account has its own addresses and
+// bank accounts in Lob, so IDs from one account cannot be used with another.
+func (l *lob) WithAccount(name string) *lob {
+	c := *l
+	c.account = name
+	return &c
+}
+
+// WithEnvironment returns a copy of the client that uses the given environment's keys.
+func (l *lob) WithEnvironment(env Environment) *lob {
+	c := *l
+	c.Environment = env
+	return &c
+}
+
+// environment returns the environment requests are sent to: the client's Environment if set,
+// otherwise the test environment for accounts and Credentials, and the environment of APIKey
+// for a single key.
+func (l *lob) environment() Environment {
+	switch {
+	case l.Environment != "":
+		return l.Environment
+	case l.account != "" || l.Credentials != nil:
+		return EnvironmentTest
+	}
+	return KeyEnvironment(l.APIKey)
+}
+
+// apiKey returns the key to authenticate requests with, refusing live keys unless AllowLive is
+// set and keys that do not belong to the client's environment.
+func (l *lob) apiKey() (string, error) {
+	env := l.environment()
+	key := l.APIKey
+	switch {
+	case l.Credentials != nil:
+		var err error
+		if key, err = l.Credentials.APIKey(l.account, env); err != nil {
+			return "", err
+		}
+	case l.account != "":
+		account, ok := l.Accounts[l.account]
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrUnknownAccount, l.account)
+		}
+		key = account.Key(env)
+	}
+
+	switch {
+	case key == "":
+		return "", fmt.Errorf("%w for the %s environment", ErrNoAPIKey, env)
+	case KeyEnvironment(key) != env:
+		return "", fmt.Errorf("%w: %s key used in the %s environment", ErrKeyEnvironmentMismatch, KeyEnvironment(key), env)
+	case env == EnvironmentLive && !l.AllowLive:
+		return "", ErrLiveKeyNotAllowed
+	}
+	return key, nil
+}
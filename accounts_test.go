@@ -0,0 +1,72 @@
+package lob
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKeyEnvironment(t *testing.T) {
+	if env := KeyEnvironment("test_0dc8d51e0acffcb1880e0f19c79b2f5b0cc"); env != EnvironmentTest {
+		t.Errorf("Expected a test key, got %s", env)
+	}
+	if env := KeyEnvironment("live_0dc8d51e0acffcb1880e0f19c79b2f5b0cc"); env != EnvironmentLive {
+		t.Errorf("Expected a live key, got %s", env)
+	}
+}
+
+func TestAccountRouting(t *testing.T) {
+	var usedKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usedKey, _, _ = r.BasicAuth()
+		w.Write([]byte(`{"id": "adr_123"}`))
+	}))
+	defer server.Close()
+
+	l := NewLob(server.URL+"/", "test_default", testUserAgent)
+	l.Accounts = map[string]Account{
+		"seed":    {TestKey: "test_seed", LiveKey: "live_seed"},
+		"holding": {TestKey: "test_holding"},
+	}
+
+	tests := []struct {
+		name        string
+		client      *lob
+		expectedKey string
+		expectedErr error
+	}{
+		{"default key", l, "test_default", nil},
+		{"account test key", l.WithAccount("seed"), "test_seed", nil},
+		{"other account", l.WithAccount("holding"), "test_holding", nil},
+		{"unknown account", l.WithAccount("acme"), "", ErrUnknownAccount},
+		{"live without AllowLive", l.WithAccount("seed").WithEnvironment(EnvironmentLive), "", ErrLiveKeyNotAllowed},
+		{"account without a live key", l.WithAccount("holding").WithEnvironment(EnvironmentLive), "", ErrNoAPIKey},
+		{"test key in live environment", l.WithEnvironment(EnvironmentLive), "", ErrKeyEnvironmentMismatch},
+		{"live key in test environment", NewLob(server.URL+"/", "live_default", testUserAgent).WithEnvironment(EnvironmentTest), "", ErrKeyEnvironmentMismatch},
+		{"live key alone", NewLob(server.URL+"/", "live_default", testUserAgent), "", ErrLiveKeyNotAllowed},
+	}
+
+	for _, test := range tests {
+		usedKey = ""
+		_, err := test.client.GetAddress("adr_123")
+		if !errors.Is(err, test.expectedErr) {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectedErr, err)
+		}
+		if usedKey != test.expectedKey {
+			t.Errorf("%s: expected key %q to be used, got %q", test.name, test.expectedKey, usedKey)
+		}
+	}
+
+	live := l.WithAccount("seed").WithEnvironment(EnvironmentLive)
+	live.AllowLive = true
+	if _, err := live.GetAddress("adr_123"); err != nil {
+		t.Fatal(err)
+	}
+	if usedKey != "live_seed" {
+		t.Errorf("Expected the live key once allowed, got %q", usedKey)
+	}
+	if l.account != "" || l.Environment != "" || l.AllowLive {
+		t.Error("Expected the original client to be unchanged")
+	}
+}
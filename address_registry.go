@@ -0,0 +1,276 @@
+package lob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// AddressFingerprintKey is the metadata key under which AddressRegistry records an address's
+// fingerprint in Lob, so that the addresses can be found again by another process.
+const AddressFingerprintKey = "address_fingerprint"
+
+// AddressFingerprint returns a stable identifier for the printed contents of an address: the
+// recipient, company and postal address. US addresses are normalized first, so differences in
+// case, punctuation or abbreviations do not produce a new fingerprint. Contact details, the
+// description and metadata are not part of the fingerprint.
+func AddressFingerprint(address *Address) string {
+	normalized, err := NormalizeUSAddress(address)
+	if err != nil {
+		normalized = address
+	}
+	fields := []string{
+		canonicalText(&normalized.AddressLine1),
+		canonicalText(normalized.AddressLine2),
+		canonicalText(normalized.AddressCity),
+		canonicalText(normalized.AddressState),
+		canonicalText(normalized.AddressZip),
+		canonicalText(normalized.AddressCountry),
+		canonicalText(normalized.Name),
+		canonicalText(normalized.Company),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalText(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.ToUpper(collapseSpaces(*s))
+}
+
+// AddressStore remembers the Lob address ID created for each address fingerprint.
+type AddressStore interface {
+	// Get returns the address ID stored for the fingerprint, if any.
+	Get(fingerprint string) (id string, ok bool, err error)
+	// Put records the address ID for the fingerprint.
+	Put(fingerprint, id string) error
+	// Remove forgets the address with the given ID.
+	Remove(id string) error
+}
+
+type memoryAddressStore struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewMemoryAddressStore returns an AddressStore that keeps fingerprints in memory.
+func NewMemoryAddressStore() AddressStore {
+	return &memoryAddressStore{ids: make(map[string]string)}
+}
+
+func (s *memoryAddressStore) Get(fingerprint string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.ids[fingerprint]
+	return id, ok, nil
+}
+
+func (s *memoryAddressStore) Put(fingerprint, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids[fingerprint] = id
+	return nil
+}
+
+func (s *memoryAddressStore) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for fingerprint, storedID := range s.ids {
+		if storedID == id {
+			delete(s.ids, fingerprint)
+		}
+	}
+	return nil
+}
+
+// AddressRegistry wraps a Lob client so that creating an address which already exists in Lob
+// returns the existing address instead of a duplicate. It also lets CreateCheck take inline
+// addresses, which are resolved to address IDs the same way.
+type AddressRegistry struct {
+	Lob
+	store AddressStore
+
+	mu      sync.Mutex
+	pending map[string]*fingerprintLock
+}
+
+// fingerprintLock serializes the calls creating addresses with one fingerprint.
+type fingerprintLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// NewAddressRegistry wraps l with address de-duplication backed by store. If store is nil,
+// fingerprints are kept in memory.
+func NewAddressRegistry(l Lob, store AddressStore) *AddressRegistry {
+	if store == nil {
+		store = NewMemoryAddressStore()
+	}
+	return &AddressRegistry{Lob: l, store: store, pending: make(map[string]*fingerprintLock)}
+}
+
+// Warm loads the fingerprints recorded in the metadata of the newest count existing addresses, or
+// of every address if count is 0 or less, into the store, so that addresses created by another
+// process or a previous run are reused. Only the first page of up to 100 addresses is read unless
+// the wrapped Lob is a Pager.
+func (r *AddressRegistry) Warm(count int) error {
+	limit := maxListCount
+	if count > 0 && count < limit {
+		limit = count
+	}
+	pager, _ := r.Lob.(Pager)
+	read := 0
+	resp, err := r.Lob.ListAddresses(limit)
+	for ; err == nil; resp, err = pager.ListAddressesPage(resp.NextURL) {
+		for _, address := range resp.Data {
+			if count > 0 && read == count {
+				return nil
+			}
+			read++
+			fingerprint := address.Metadata[AddressFingerprintKey]
+			if fingerprint == "" || address.ID == "" || (address.Deleted != nil && *address.Deleted) {
+				continue
+			}
+			if err := r.store.Put(fingerprint, address.ID); err != nil {
+				return err
+			}
+		}
+		if pager == nil || resp.NextURL == "" || (count > 0 && read == count) {
+			return nil
+		}
+	}
+	return err
+}
+
+// lock locks the fingerprint, so that concurrent calls creating the same address create it once
+// while calls for other addresses go ahead. It returns the function that unlocks it.
+func (r *AddressRegistry) lock(fingerprint string) (unlock func()) {
+	r.mu.Lock()
+	l, ok := r.pending[fingerprint]
+	if !ok {
+		l = new(fingerprintLock)
+		r.pending[fingerprint] = l
+	}
+	l.waiters++
+	r.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		r.mu.Lock()
+		if l.waiters--; l.waiters == 0 {
+			delete(r.pending, fingerprint)
+		}
+		r.mu.Unlock()
+	}
+}
+
+// CreateAddress returns the existing Lob address with the same fingerprint, or creates a new one
+// tagged with its fingerprint. The given address is not modified. The stored address is only
+// replaced when Lob reports it deleted or not found; other errors looking it up are returned, so
+// that an outage does not create duplicates.
+func (r *AddressRegistry) CreateAddress(address *Address) (*Address, error) {
+	fingerprint := AddressFingerprint(address)
+	unlock := r.lock(fingerprint)
+	defer unlock()
+
+	id, ok, err := r.store.Get(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		existing, err := r.Lob.GetAddress(id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return existing, err
+		}
+		if err == nil && (existing.Deleted == nil || !*existing.Deleted) {
+			return existing, nil
+		}
+		// The stored address is gone from Lob; forget it and create a new one.
+		if err := r.store.Remove(id); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := r.Lob.CreateAddress(newTaggedAddress(address, fingerprint))
+	if err != nil {
+		return created, err
+	}
+	if err := r.store.Put(fingerprint, created.ID); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// newTaggedAddress returns a request to create the address with its fingerprint in its metadata.
+// Only the fields Lob accepts are copied, so an address read back from Lob can be created again.
+func newTaggedAddress(address *Address, fingerprint string) *Address {
+	tagged := &Address{
+		Name:           address.Name,
+		Company:        address.Company,
+		AddressLine1:   address.AddressLine1,
+		AddressLine2:   address.AddressLine2,
+		AddressCity:    address.AddressCity,
+		AddressState:   address.AddressState,
+		AddressZip:     address.AddressZip,
+		AddressCountry: address.AddressCountry,
+		Description:    address.Description,
+		Email:          address.Email,
+		Phone:          address.Phone,
+		Metadata:       make(map[string]string, len(address.Metadata)+1),
+	}
+	for k, v := range address.Metadata {
+		tagged.Metadata[k] = v
+	}
+	tagged.Metadata[AddressFingerprintKey] = fingerprint
+	return tagged
+}
+
+// DeleteAddress deletes the address from Lob and then forgets its fingerprint. If Lob fails to
+// delete the address for any reason other than not having it, the fingerprint is kept, so the
+// address is still reused.
+func (r *AddressRegistry) DeleteAddress(id string) error {
+	err := r.Lob.DeleteAddress(id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err := r.store.Remove(id); err != nil {
+		return err
+	}
+	return err
+}
+
+// CreateCheck resolves the request's inline To and From addresses to address IDs, reusing
+// existing addresses where possible, and then creates the check. Like the client, it returns a
+// check holding the error if an address can't be created.
+func (r *AddressRegistry) CreateCheck(req *CreateCheckRequest) (*Check, error) {
+	resolved := *req
+	for _, ref := range []*AddressRef{&resolved.To, &resolved.From} {
+		if address, err := r.resolve(ref); err != nil {
+			check := new(Check)
+			if address != nil {
+				check.Error = address.Error
+			}
+			return check, err
+		}
+	}
+	return r.Lob.CreateCheck(&resolved)
+}
+
+// resolve replaces an inline address with the ID of the address CreateAddress returns for it. If
+// that fails, it returns what CreateAddress returned.
+func (r *AddressRegistry) resolve(ref *AddressRef) (*Address, error) {
+	if ref.ID != "" || ref.Address == nil {
+		return nil, nil
+	}
+	address, err := r.CreateAddress(ref.Address)
+	if err != nil {
+		return address, err
+	}
+	*ref = AddressID(address.ID)
+	return address, nil
+}
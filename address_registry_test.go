@@ -0,0 +1,207 @@
+package lob
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAddressRegistry(t *testing.T) {
+	fake := NewFakeLob()
+	registry := NewAddressRegistry(fake, nil)
+
+	first, err := registry.CreateAddress(&Address{
+		Name:         nullString("Lobster Test"),
+		AddressLine1: "1005 West Burnside Street",
+		AddressCity:  nullString("Portland"),
+		AddressState: nullString("OR"),
+		AddressZip:   nullString("97209"),
+	})
+	if err != nil {
+		t.Fatalf("Could not create address: %s", err.Error())
+	}
+	if first.Metadata[AddressFingerprintKey] == "" {
+		t.Error("Expected the address to be tagged with its fingerprint")
+	}
+
+	second, err := registry.CreateAddress(&Address{
+		Name:         nullString("LOBSTER  TEST"),
+		AddressLine1: "1005 W. Burnside St",
+		AddressCity:  nullString("PORTLAND"),
+		AddressState: nullString("Oregon"),
+		AddressZip:   nullString("97209"),
+	})
+	if err != nil {
+		t.Fatalf("Could not create address: %s", err.Error())
+	}
+	if second.ID != first.ID {
+		t.Errorf("Expected address %s to be reused, got %s", first.ID, second.ID)
+	}
+	if len(fake.addresses) != 1 {
+		t.Errorf("Expected 1 address in Lob, got %d", len(fake.addresses))
+	}
+
+	bankAccount, err := registry.CreateBankAccount(&CreateBankAccountRequest{
+		AccountNumber: "1132234455",
+		RoutingNumber: "255077370",
+		Signatory:     "Big Bird",
+		AccountType:   AccountTypeCompany,
+	})
+	if err != nil {
+		t.Fatalf("Could not create bank account: %s", err.Error())
+	}
+	check, err := registry.CreateCheck(&CreateCheckRequest{
+		Amount:        MustParseMoney("100.00"),
+		BankAccountID: bankAccount.ID,
+		From:          AddressID(first.ID),
+		To: InlineAddress(&Address{
+			Name:         nullString("Lobster Test"),
+			AddressLine1: "1005 W Burnside St",
+			AddressZip:   nullString("97209"),
+			AddressCity:  nullString("Portland"),
+			AddressState: nullString("OR"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Could not create check: %s", err.Error())
+	}
+	if check.To.ID != first.ID {
+		t.Errorf("Expected check to be sent to address %s, got %s", first.ID, check.To.ID)
+	}
+
+	if err := registry.DeleteAddress(first.ID); err != nil {
+		t.Fatalf("Could not delete address: %s", err.Error())
+	}
+	later := fake.Now().Add(time.Hour)
+	fake.Now = func() time.Time { return later }
+	third, err := registry.CreateAddress(first)
+	if err != nil {
+		t.Fatalf("Could not create address: %s", err.Error())
+	}
+	if third.ID == first.ID {
+		t.Error("Expected a deleted address to be created again")
+	}
+	if !third.DateCreated.Equal(later) {
+		t.Errorf("Expected the address to be created at %s, not to copy %s", later, first.DateCreated)
+	}
+}
+
+func TestAddressRegistryLookupErrors(t *testing.T) {
+	fake := NewFakeLob()
+	fake.Faults = NewFaults()
+	registry := NewAddressRegistry(fake, nil)
+	address := &Address{
+		Name:         nullString("Lobster Test"),
+		AddressLine1: "1005 W Burnside St",
+		AddressCity:  nullString("Portland"),
+		AddressState: nullString("OR"),
+		AddressZip:   nullString("97209"),
+	}
+	first, err := registry.CreateAddress(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Faults.FailNext("GetAddress", 1, Fault{StatusCode: http.StatusInternalServerError})
+	var apiErr *APIError
+	if _, err := registry.CreateAddress(address); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected the lookup's 500, got %v", err)
+	}
+	if addresses := fake.Addresses(); len(addresses) != 1 {
+		t.Errorf("Expected a failed lookup not to create another address, got %d", len(addresses))
+	}
+	if again, err := registry.CreateAddress(address); err != nil || again.ID != first.ID {
+		t.Errorf("Expected address %s to still be reused, got %+v, %v", first.ID, again, err)
+	}
+}
+
+func TestAddressRegistryWarm(t *testing.T) {
+	fake := NewFakeLob()
+	var addresses []Address
+	for i := 0; i < 150; i++ {
+		address := Address{
+			Name:         nullString(fmt.Sprintf("Lobster Test %d", i)),
+			AddressLine1: "1005 W Burnside St",
+			AddressCity:  nullString("Portland"),
+			AddressState: nullString("OR"),
+			AddressZip:   nullString("97209"),
+		}
+		address.Metadata = map[string]string{AddressFingerprintKey: AddressFingerprint(&address)}
+		addresses = append(addresses, address)
+	}
+	fake.Seed(FakeState{Addresses: addresses})
+	oldest := fake.Addresses()[0]
+
+	store := NewMemoryAddressStore()
+	if err := NewAddressRegistry(fake, store).Warm(120); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := store.Get(oldest.Metadata[AddressFingerprintKey]); ok {
+		t.Error("Expected only the newest 120 addresses to be loaded")
+	}
+
+	registry := NewAddressRegistry(fake, nil)
+	if err := registry.Warm(0); err != nil {
+		t.Fatal(err)
+	}
+	reused, err := registry.CreateAddress(&addresses[0])
+	if err != nil || reused.ID != oldest.ID {
+		t.Errorf("Expected the oldest address %s on the second page to be reused, got %+v, %v", oldest.ID, reused, err)
+	}
+	if n := len(fake.Addresses()); n != 150 {
+		t.Errorf("Expected no addresses to be created, got %d", n-150)
+	}
+}
+
+func TestAddressRegistryDeleteErrors(t *testing.T) {
+	fake := NewFakeLob()
+	fake.Faults = NewFaults()
+	registry := NewAddressRegistry(fake, nil)
+	address := &Address{
+		Name:         nullString("Lobster Test"),
+		AddressLine1: "1005 W Burnside St",
+		AddressCity:  nullString("Portland"),
+		AddressState: nullString("OR"),
+		AddressZip:   nullString("97209"),
+	}
+	first, err := registry.CreateAddress(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Faults.FailNext("DeleteAddress", 1, Fault{StatusCode: http.StatusServiceUnavailable})
+	if err := registry.DeleteAddress(first.ID); err == nil {
+		t.Fatal("Expected the delete's 503")
+	}
+	if again, err := registry.CreateAddress(address); err != nil || again.ID != first.ID {
+		t.Errorf("Expected address %s to still be reused after a failed delete, got %+v, %v", first.ID, again, err)
+	}
+
+	if err := fake.DeleteAddress(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.DeleteAddress(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting an address Lob already deleted, got %v", err)
+	}
+	if _, ok, _ := registry.store.Get(AddressFingerprint(address)); ok {
+		t.Error("Expected the fingerprint of an address Lob no longer has to be forgotten")
+	}
+}
+
+func TestAddressRegistryCreateCheckErrors(t *testing.T) {
+	registry := NewAddressRegistry(NewFakeLob(), nil)
+	check, err := registry.CreateCheck(&CreateCheckRequest{
+		Amount:        MustParseMoney("100.00"),
+		BankAccountID: "bank_123",
+		From:          AddressID("adr_123"),
+		To:            InlineAddress(&Address{Name: nullString("Lobster Test")}),
+	})
+	if err == nil {
+		t.Fatal("Expected an invalid inline address to fail")
+	}
+	if check == nil || check.Error == nil {
+		t.Errorf("Expected a check holding the error, like the client returns, got %+v", check)
+	}
+}
@@ -1,9 +1,11 @@
 package lob
 
 import (
+	"encoding/json"
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Error struct {
@@ -13,30 +15,132 @@ type Error struct {
 
 // Address represents an address stored in the Lob's system.
 type Address struct {
-	Error          *Error            `json:"error"`
-	AddressCity    *string           `json:"address_city"`
-	AddressCountry *string           `json:"address_country"`
-	AddressLine1   string            `json:"address_line1"`
-	AddressLine2   *string           `json:"address_line2"`
-	AddressState   *string           `json:"address_state"`
-	AddressZip     *string           `json:"address_zip"`
-	Company        *string           `json:"company"`
-	DateCreated    string            `json:"date_created"`
-	DateModified   string            `json:"date_modified"`
-	Deleted        *bool             `json:"deleted"`
-	Description    *string           `json:"description"`
-	Email          *string           `json:"email"`
-	ID             string            `json:"id"`
-	Metadata       map[string]string `json:"metadata"`
-	Name           *string           `json:"name"`
-	Object         string            `json:"object"`
-	Phone          *string           `json:"phone"`
+	Error          *Error            `json:"error,omitempty"`
+	AddressCity    *string           `json:"address_city,omitempty"`
+	AddressCountry *string           `json:"address_country,omitempty"`
+	AddressLine1   string            `json:"address_line1,omitempty"`
+	AddressLine2   *string           `json:"address_line2,omitempty"`
+	AddressState   *string           `json:"address_state,omitempty"`
+	AddressZip     *string           `json:"address_zip,omitempty"`
+	Company        *string           `json:"company,omitempty"`
+	DateCreated    time.Time         `json:"date_created,omitzero"`
+	DateModified   time.Time         `json:"date_modified,omitzero"`
+	Deleted        *bool             `json:"deleted,omitempty"`
+	Description    *string           `json:"description,omitempty"`
+	Email          *string           `json:"email,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	Name           *string           `json:"name,omitempty"`
+	Object         string            `json:"object,omitempty"`
+	Phone          *string           `json:"phone,omitempty"`
+}
+
+// Validate checks the address against the constraints Lob documents for addresses, returning
+// ValidationErrors listing every invalid field.
+func (address *Address) Validate() error {
+	v := new(validator)
+	address.validate(v, "")
+	return v.err()
+}
+
+// validate adds the address's field errors to v, naming fields under prefix for inline addresses.
+func (address *Address) validate(v *validator, prefix string) {
+	field := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "[" + name + "]"
+	}
+
+	if !nonEmpty(address.Name) && !nonEmpty(address.Company) {
+		v.add(field("name"), "or company is required")
+	}
+	v.maxLength(field("name"), address.Name, 40)
+	v.maxLength(field("company"), address.Company, 40)
+	v.required(field("address_line1"), strings.TrimSpace(address.AddressLine1) != "")
+	v.maxLength(field("address_line1"), &address.AddressLine1, 200)
+	v.maxLength(field("address_line2"), address.AddressLine2, 200)
+	v.maxLength(field("address_city"), address.AddressCity, 200)
+	v.maxLength(field("address_state"), address.AddressState, 200)
+	v.maxLength(field("email"), address.Email, 100)
+	v.maxLength(field("phone"), address.Phone, 40)
+	v.maxLength(field("description"), address.Description, 255)
+
+	country := "US"
+	if nonEmpty(address.AddressCountry) {
+		country = *address.AddressCountry
+		v.matches(field("address_country"), address.AddressCountry, countryCodePattern, "a 2 letter country code")
+	}
+	if country != "US" {
+		v.maxLength(field("address_zip"), address.AddressZip, 40)
+		return
+	}
+
+	v.required(field("address_city"), nonEmpty(address.AddressCity))
+	v.required(field("address_state"), nonEmpty(address.AddressState))
+	if nonEmpty(address.AddressState) {
+		if _, ok := usStateNames[strings.ToUpper(*address.AddressState)]; !ok {
+			v.add(field("address_state"), "must be a 2 letter US state code")
+		}
+	}
+	v.required(field("address_zip"), nonEmpty(address.AddressZip))
+	if nonEmpty(address.AddressZip) {
+		v.matches(field("address_zip"), address.AddressZip, usZipPattern, "a ZIP code of the form 12345 or 12345-1234")
+	}
+}
+
+// AddressRef refers to an address in a request, either by the ID of an address already stored
+// in Lob or by including the full address inline.
+type AddressRef struct {
+	ID      string
+	Address *Address
+}
+
+// AddressID refers to the stored address with the given ID.
+func AddressID(id string) AddressRef {
+	return AddressRef{ID: id}
+}
+
+// InlineAddress refers to the given address, which Lob stores along with the request.
+func InlineAddress(address *Address) AddressRef {
+	return AddressRef{Address: address}
+}
+
+// IsZero reports whether the reference refers to no address at all.
+func (r AddressRef) IsZero() bool {
+	return r.ID == "" && r.Address == nil
+}
+
+// MarshalJSON encodes the reference the way Lob expects it: an address ID string, or an address
+// object for inline addresses.
+func (r AddressRef) MarshalJSON() ([]byte, error) {
+	if r.ID != "" || r.Address == nil {
+		return json.Marshal(r.ID)
+	}
+	return json.Marshal(r.Address)
+}
+
+// UnmarshalJSON decodes either an address ID string or an inline address object.
+func (r *AddressRef) UnmarshalJSON(data []byte) error {
+	*r = AddressRef{}
+	if len(data) > 0 && data[0] == '{' {
+		r.Address = new(Address)
+		return json.Unmarshal(data, r.Address)
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, &r.ID)
 }
 
 // CreateAddress creates an address in Lob's system.
 func (lob *lob) CreateAddress(address *Address) (*Address, error) {
 	resp := new(Address)
-	if err := lob.post("addresses", json2form(*address), resp); err != nil {
+	if err := address.Validate(); err != nil {
+		resp.Error = validationError(err)
+		return resp, err
+	}
+	if err := lob.post("addresses", address, resp); err != nil {
 		return resp, err
 	}
 	return resp, nil
@@ -86,7 +190,7 @@ func (lob *lob) ListAddresses(count int) (*ListAddressesResponse, error) {
 
 	resp := new(ListAddressesResponse)
 	if err := lob.get("addresses/", map[string]string{
-		"limit":  strconv.Itoa(count),
+		"limit": strconv.Itoa(count),
 	}, resp); err != nil {
 		return nil, err
 	}
@@ -95,12 +199,12 @@ func (lob *lob) ListAddresses(count int) (*ListAddressesResponse, error) {
 
 // AddressVerificationRequest validates the given subset of info from an address.
 type USAddressVerificationRequest struct {
-	Recipient    *string `json:"recipient"`
-	AddressLine1 *string `json:"primary_line"`
-	AddressLine2 *string `json:"secondary_line"`
-	AddressCity  *string `json:"city"`
-	AddressState *string `json:"state"`
-	AddressZip   *string `json:"zip_code"`
+	Recipient    *string `json:"recipient,omitempty"`
+	AddressLine1 *string `json:"primary_line,omitempty"`
+	AddressLine2 *string `json:"secondary_line,omitempty"`
+	AddressCity  *string `json:"city,omitempty"`
+	AddressState *string `json:"state,omitempty"`
+	AddressZip   *string `json:"zip_code,omitempty"`
 }
 
 // USAddressVerificationResponse gives the response from attempting to verify a US address.
@@ -168,12 +272,12 @@ func (lob *lob) VerifyUSAddress(address *Address) (*USAddressVerificationRespons
 		AddressZip:   address.AddressZip,
 	}
 	resp := new(USAddressVerificationResponse)
-	if err := lob.post("us_verifications", json2form(req), resp); err != nil {
+	if err := lob.post("us_verifications", req, resp); err != nil {
 		return nil, err
 	}
 
 	// in test, fill in components
-	if strings.HasPrefix(lob.APIKey, "test") {
+	if lob.environment() == EnvironmentTest {
 		streetSplit := strings.Split(address.AddressLine1, " ")
 		if len(streetSplit) > 2 {
 			resp.Components.PrimaryNumber = streetSplit[0]
@@ -1,14 +1,21 @@
 package lob
 
-import "strconv"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // BankAccount represents a bank account in lob's system.
 type BankAccount struct {
 	Error         *Error            `json:"error"`
 	AccountNumber string            `json:"account_number"`
+	AccountType   string            `json:"account_type"`
 	BankName      string            `json:"bank_name"`
-	DateCreated   string            `json:"date_created"`
-	DateModified  string            `json:"date_modified"`
+	DateCreated   time.Time         `json:"date_created"`
+	DateModified  time.Time         `json:"date_modified"`
+	Deleted       bool              `json:"deleted"`
 	Description   *string           `json:"description"`
 	ID            string            `json:"id"`
 	Metadata      map[string]string `json:"metadata"`
@@ -21,19 +28,92 @@ type BankAccount struct {
 // CreateBankAccountRequest request has the parameters needed to submit a bank account creation
 // request to Lob.
 type CreateBankAccountRequest struct {
-	Description   *string           `json:"description"`
-	RoutingNumber string            `json:"routing_number"`
-	AccountNumber string            `json:"account_number"`
-	Signatory     string            `json:"signatory"`
-	AccountType   string            `json:"account_type"`
-	Metadata      map[string]string `json:"metadata"`
+	Description   *string           `json:"description,omitempty"`
+	RoutingNumber string            `json:"routing_number,omitempty"`
+	AccountNumber string            `json:"account_number,omitempty"`
+	Signatory     string            `json:"signatory,omitempty"`
+	AccountType   string            `json:"account_type,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// String formats the bank account with its account and routing numbers masked, so that it can
+// be logged.
+func (b BankAccount) String() string {
+	return fmt.Sprintf("%+v", b.masked())
+}
+
+// GoString is like String, for the %#v verb.
+func (b BankAccount) GoString() string {
+	return "lob.BankAccount" + strings.TrimPrefix(fmt.Sprintf("%#v", b.masked()), "lob.bankAccount")
+}
+
+// bankAccount has BankAccount's fields without its methods, so it can be formatted normally.
+type bankAccount BankAccount
+
+func (b BankAccount) masked() bankAccount {
+	b.AccountNumber = maskAccountNumber(b.AccountNumber)
+	b.RoutingNumber = maskAccountNumber(b.RoutingNumber)
+	return bankAccount(b)
+}
+
+// String formats the request with its account and routing numbers masked, so that it can be
+// logged.
+func (req CreateBankAccountRequest) String() string {
+	return fmt.Sprintf("%+v", req.masked())
+}
+
+// GoString is like String, for the %#v verb.
+func (req CreateBankAccountRequest) GoString() string {
+	return "lob.CreateBankAccountRequest" + strings.TrimPrefix(fmt.Sprintf("%#v", req.masked()), "lob.createBankAccountRequest")
+}
+
+// createBankAccountRequest has CreateBankAccountRequest's fields without its methods.
+type createBankAccountRequest CreateBankAccountRequest
+
+func (req CreateBankAccountRequest) masked() createBankAccountRequest {
+	req.AccountNumber = maskAccountNumber(req.AccountNumber)
+	req.RoutingNumber = maskAccountNumber(req.RoutingNumber)
+	return createBankAccountRequest(req)
+}
+
+// Bank account types that lob supports.
+const (
+	AccountTypeCompany    = "company"
+	AccountTypeIndividual = "individual"
+)
+
+// Validate checks the request against the constraints Lob documents for bank accounts, returning
+// ValidationErrors listing every invalid field.
+func (req *CreateBankAccountRequest) Validate() error {
+	v := new(validator)
+
+	v.required("routing_number", req.RoutingNumber != "")
+	if req.RoutingNumber != "" && !validRoutingNumber(req.RoutingNumber) {
+		v.add("routing_number", "must be a valid 9 digit ABA routing number")
+	}
+	v.required("account_number", req.AccountNumber != "")
+	if req.AccountNumber != "" {
+		v.matches("account_number", &req.AccountNumber, accountNumberPattern, "between 1 and 17 digits")
+	}
+	v.required("signatory", strings.TrimSpace(req.Signatory) != "")
+	v.maxLength("signatory", &req.Signatory, 30)
+	v.required("account_type", req.AccountType != "")
+	if req.AccountType != "" {
+		v.oneOf("account_type", &req.AccountType, AccountTypeCompany, AccountTypeIndividual)
+	}
+	v.maxLength("description", req.Description, 255)
+
+	return v.err()
 }
 
 // CreateBankAccount creates a new bank account in Lob's system.
 func (l *lob) CreateBankAccount(account *CreateBankAccountRequest) (*BankAccount, error) {
+	if err := account.Validate(); err != nil {
+		return &BankAccount{Error: validationError(err)}, err
+	}
 	resp := new(BankAccount)
-	if err := l.post("bank_accounts/", json2form(*account), resp); err != nil {
-		return nil, err
+	if err := l.post("bank_accounts/", account, resp); err != nil {
+		return resp, err
 	}
 	return resp, nil
 }
@@ -42,7 +122,7 @@ func (l *lob) CreateBankAccount(account *CreateBankAccountRequest) (*BankAccount
 func (l *lob) GetBankAccount(id string) (*BankAccount, error) {
 	resp := new(BankAccount)
 	if err := l.get("bank_accounts/"+id, nil, resp); err != nil {
-		return nil, err
+		return resp, err
 	}
 	return resp, nil
 }
@@ -64,7 +144,7 @@ func (l *lob) ListBankAccounts(count int) (*ListBankAccountsResponse, error) {
 
 	resp := new(ListBankAccountsResponse)
 	if err := l.get("bank_accounts", map[string]string{
-		"limit":  strconv.Itoa(count),
+		"limit": strconv.Itoa(count),
 	}, resp); err != nil {
 		return nil, err
 	}
@@ -1,46 +1,97 @@
 package lob
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
 	"time"
 )
 
 // Check represents a printed check in Lob's system.
 type Check struct {
-	Error                *Error              `json:"error"`
-	Amount               float64             `json:"amount"`
-	BankAccount          *BankAccount        `json:"bank_account"`
-	CheckBottom          *string             `json:"check_bottom"`
-	CheckNumber          int                 `json:"check_number"`
-	Data                 map[string]string   `json:"data"`
-	DateCreated          string              `json:"date_created"`
-	DateModified         string              `json:"date_modified"`
-	Description          string              `json:"description"`
-	ExpectedDeliveryDate string              `json:"expected_delivery_date"`
-	SendDate             time.Time           `json:"send_date"`
-	From                 *Address            `json:"from"`
-	ID                   string              `json:"id"`
-	Logo                 *string             `json:"logo"`
-	MailType             *string             `json:"mail_type"`
-	Memo                 string              `json:"memo"`
-	Message              *string             `json:"message"`
-	Metadata             map[string]string   `json:"metadata"`
-	Name                 string              `json:"name"`
-	Object               string              `json:"object"`
-	Thumbnails           []map[string]string `json:"thumbnails"`
-	To                   *Address            `json:"to"`
-	Tracking             *Tracking           `json:"tracking"`
-	URL                  string              `json:"url"`
+	Error                 *Error                 `json:"error"`
+	Amount                Money                  `json:"amount"`
+	Attachment            *File                  `json:"attachment"`
+	BankAccount           *BankAccount           `json:"bank_account"`
+	Carrier               string                 `json:"carrier"`
+	CheckBottom           *string                `json:"check_bottom"`
+	CheckBottomTemplateID *string                `json:"check_bottom_template_id"`
+	CheckNumber           int                    `json:"check_number"`
+	Data                  map[string]string      `json:"data"`
+	DateCreated           time.Time              `json:"date_created"`
+	DateModified          time.Time              `json:"date_modified"`
+	Deleted               bool                   `json:"deleted"`
+	Description           string                 `json:"description"`
+	ExpectedDeliveryDate  Date                   `json:"expected_delivery_date"`
+	From                  *Address               `json:"from"`
+	ID                    string                 `json:"id"`
+	Logo                  *string                `json:"logo"`
+	MailType              *string                `json:"mail_type"`
+	Memo                  string                 `json:"memo"`
+	MergeVariables        map[string]interface{} `json:"merge_variables"`
+	Message               *string                `json:"message"`
+	Metadata              map[string]string      `json:"metadata"`
+	Name                  string                 `json:"name"`
+	Object                string                 `json:"object"`
+	SendDate              Timestamp              `json:"send_date"`
+	Thumbnails            []Thumbnail            `json:"thumbnails"`
+	To                    *Address               `json:"to"`
+	Tracking              *Tracking              `json:"tracking"`
+	TrackingEvents        []TrackingEvent        `json:"tracking_events"`
+	URL                   string                 `json:"url"`
+}
+
+// Cancellable reports whether the check can still be cancelled at the given time. Lob only
+// cancels checks that have not been deleted and whose send date, which is the end of the
+// cancellation window for checks that are not scheduled, has not yet passed.
+func (c *Check) Cancellable(now time.Time) bool {
+	return !c.Deleted && !c.SendDate.IsZero() && now.Before(c.SendDate.Time)
+}
+
+// Thumbnail links to renderings of one page of a check in three sizes.
+type Thumbnail struct {
+	Large  string `json:"large"`
+	Medium string `json:"medium"`
+	Small  string `json:"small"`
 }
 
 // Tracking provides information on shipment tracking for a check.
 type Tracking struct {
-	Carrier        string        `json:"carrier"`
-	Events         []interface{} `json:"events"`
-	ID             string        `json:"id"`
-	Link           *string       `json:"link"`
-	Object         string        `json:"object"`
-	TrackingNumber string        `json:"tracking_number"`
+	Carrier        string          `json:"carrier"`
+	Events         []TrackingEvent `json:"events"`
+	ID             string          `json:"id"`
+	Link           *string         `json:"link"`
+	Object         string          `json:"object"`
+	TrackingNumber string          `json:"tracking_number"`
+}
+
+// TrackingEvent is a scan of a check by the carrier, such as "In Transit" or "Delivered".
+type TrackingEvent struct {
+	DateCreated  time.Time `json:"date_created"`
+	DateModified time.Time `json:"date_modified"`
+	ID           string    `json:"id"`
+	Location     *string   `json:"location"`
+	Name         string    `json:"name"`
+	Object       string    `json:"object"`
+	Time         time.Time `json:"time"`
+	Type         string    `json:"type"`
+}
+
+// MaxSendDateLead is how far in the future Lob allows a check to be scheduled.
+const MaxSendDateLead = 180 * 24 * time.Hour
+
+// ErrCheckNotCancellable matches, with errors.Is, the 422 *APIError returned when cancelling a
+// check that has already been sent.
+var ErrCheckNotCancellable = errors.New("check has already been sent and can no longer be cancelled")
+
+// cancelError makes a 422 from cancelling a check match ErrCheckNotCancellable. Lob answers with a
+// 422 when the check has already been sent.
+func cancelError(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		apiErr.err = ErrCheckNotCancellable
+	}
+	return err
 }
 
 // Mail types that lob supports.
@@ -51,24 +102,87 @@ const (
 
 // CreateCheckRequest specifies options for creating a check.
 type CreateCheckRequest struct {
-	Amount        float64           `json:"amount"`
-	BankAccountID string            `json:"bank_account"`
-	CheckBottom   *string           `json:"check_bottom"` // 400 chars, at bottom (cannot use with message)
-	CheckNumber   *string           `json:"check_number"`
-	Data          map[string]string `json:"data"`
-	Description   *string           `json:"description"`
-	FromAddressID string            `json:"from"`
-	Logo          *string           `json:"logo"` // url or multiform. Square, RGB / CMYK, >= 100x100, transparent bg, PNG or JPEG, and will be grayscaled
-	MailType      *string           `json:"mail_type"`
-	Memo          *string           `json:"memo"`    // 40 chars in memo line
-	Message       *string           `json:"message"` // 400 chars, at top (cannot use with check_bottom)
-	ToAddressID   string            `json:"to"`
+	Amount         Money                  `json:"amount"`
+	Attachment     *File                  `json:"attachment,omitempty"` // URL, HTML or PDF upload, printed on the pages after the check
+	BankAccountID  string                 `json:"bank_account,omitempty"`
+	CheckBottom    *string                `json:"check_bottom,omitempty"` // 400 chars, at bottom (cannot use with message)
+	CheckNumber    *string                `json:"check_number,omitempty"`
+	Data           map[string]string      `json:"data,omitempty"`
+	Description    *string                `json:"description,omitempty"`
+	From           AddressRef             `json:"from,omitzero"`
+	Logo           *File                  `json:"logo,omitempty"` // url or multiform. Square, RGB / CMYK, >= 100x100, transparent bg, PNG or JPEG, and will be grayscaled
+	MailType       *string                `json:"mail_type,omitempty"`
+	Memo           *string                `json:"memo,omitempty"`            // 40 chars in memo line
+	MergeVariables map[string]interface{} `json:"merge_variables,omitempty"` // replaces data from 2020-02-11; either may be set
+	Message        *string                `json:"message,omitempty"`         // 400 chars, at top (cannot use with check_bottom)
+	SendDate       *time.Time             `json:"send_date,omitempty"`       // schedules the check; up to 180 days ahead
+	To             AddressRef             `json:"to,omitzero"`
+}
+
+// Validate checks the request against the constraints Lob documents for checks, returning
+// ValidationErrors listing every invalid field.
+func (req *CreateCheckRequest) Validate() error {
+	return req.validate(time.Now())
+}
+
+func (req *CreateCheckRequest) validate(now time.Time) error {
+	v := new(validator)
+
+	if err := req.Amount.ValidateCheckAmount(); err != nil {
+		switch {
+		case errors.Is(err, ErrCheckAmountNotPositive):
+			v.wrap("amount", err, "must be greater than 0")
+		default:
+			v.wrap("amount", err, "must be less than or equal to %s", MaxCheckAmount)
+		}
+	}
+	v.required("bank_account", req.BankAccountID != "")
+	v.required("to", !req.To.IsZero())
+	v.required("from", !req.From.IsZero())
+	if req.To.ID == "" && req.To.Address != nil {
+		req.To.Address.validate(v, "to")
+	}
+	if req.From.ID == "" && req.From.Address != nil {
+		req.From.Address.validate(v, "from")
+	}
+
+	v.maxLength("memo", req.Memo, 40)
+	v.maxLength("message", req.Message, 400)
+	v.maxLength("check_bottom", req.CheckBottom, 400)
+	if req.Message != nil && req.CheckBottom != nil {
+		v.add("check_bottom", "cannot be used together with message")
+	}
+	v.maxLength("description", req.Description, 255)
+	if req.Data != nil && req.MergeVariables != nil {
+		v.add("merge_variables", "cannot be used together with data")
+	}
+	if req.CheckNumber != nil {
+		if n, err := strconv.Atoi(*req.CheckNumber); err != nil || n <= 0 {
+			v.add("check_number", "must be a positive integer")
+		}
+	}
+	v.imageFile("logo", req.Logo)
+	v.documentFile("attachment", req.Attachment)
+	v.oneOf("mail_type", req.MailType, MailTypeUspsFirstClass, MailTypeUpsNextDayAir)
+	if req.SendDate != nil {
+		if req.SendDate.Before(now) {
+			v.add("send_date", "must not be in the past")
+		} else if req.SendDate.Sub(now) > MaxSendDateLead {
+			v.add("send_date", "must be no more than %d days in the future", MaxSendDateLead/(24*time.Hour))
+		}
+	}
+
+	return v.err()
 }
 
 // CreateCheck requests for a new check to be printed and mailed.
 func (lob *lob) CreateCheck(req *CreateCheckRequest) (*Check, error) {
 	resp := new(Check)
-	if err := lob.post("checks/", json2form(*req), resp); err != nil {
+	if err := req.Validate(); err != nil {
+		resp.Error = validationError(err)
+		return resp, err
+	}
+	if err := lob.post("checks/", req, resp); err != nil {
 		return resp, err
 	}
 	return resp, nil
@@ -91,7 +205,7 @@ func (lob *lob) GetCheck(id string) (*Check, error) {
 func (lob *lob) CancelCheck(id string) (*CancelCheckResponse, error) {
 	resp := new(CancelCheckResponse)
 	if err := lob.delete("checks/"+id, &resp); err != nil {
-		return resp, err
+		return resp, cancelError(err)
 	}
 	return resp, nil
 }
@@ -113,7 +227,7 @@ func (lob *lob) ListChecks(count int) (*ListChecksResponse, error) {
 
 	resp := new(ListChecksResponse)
 	if err := lob.get("checks", map[string]string{
-		"limit":  strconv.Itoa(count),
+		"limit": strconv.Itoa(count),
 	}, resp); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,228 @@
+package lob
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateCheckRequestValidate(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	valid := func() *CreateCheckRequest {
+		return &CreateCheckRequest{
+			Amount:        MustParseMoney("987.65"),
+			BankAccountID: "bank_123",
+			From:          AddressID("adr_from"),
+			To:            AddressID("adr_to"),
+			Memo:          nullString("A memo"),
+			Message:       nullString("Some message"),
+			Logo:          FileURL("https://example.com/logo.png"),
+			MailType:      nullString(MailTypeUspsFirstClass),
+			CheckNumber:   nullString("12345"),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*CreateCheckRequest)
+		fields []string
+	}{
+		{"valid", func(*CreateCheckRequest) {}, nil},
+		{"missing amount", func(r *CreateCheckRequest) { r.Amount = Money{} }, []string{"amount"}},
+		{"amount too large", func(r *CreateCheckRequest) { r.Amount = MustParseMoney("1000000") }, []string{"amount"}},
+		{"missing references", func(r *CreateCheckRequest) {
+			r.BankAccountID = ""
+			r.From = AddressRef{}
+			r.To = AddressRef{}
+		}, []string{"bank_account", "to", "from"}},
+		{"long memo", func(r *CreateCheckRequest) { r.Memo = nullString(strings.Repeat("m", 41)) }, []string{"memo"}},
+		{"long message", func(r *CreateCheckRequest) { r.Message = nullString(strings.Repeat("m", 401)) }, []string{"message"}},
+		{"message and check bottom", func(r *CreateCheckRequest) { r.CheckBottom = nullString("bottom") }, []string{"check_bottom"}},
+		{"long check bottom", func(r *CreateCheckRequest) {
+			r.Message = nil
+			r.CheckBottom = nullString(strings.Repeat("b", 401))
+		}, []string{"check_bottom"}},
+		{"data and merge variables", func(r *CreateCheckRequest) {
+			r.Data = map[string]string{"name": "Harry"}
+			r.MergeVariables = map[string]interface{}{"name": "Harry"}
+		}, []string{"merge_variables"}},
+		{"logo not a URL", func(r *CreateCheckRequest) { r.Logo = FileURL("logo.png") }, []string{"logo"}},
+		{"logo not an image", func(r *CreateCheckRequest) { r.Logo = FileURL("https://example.com/logo.gif") }, []string{"logo"}},
+		{"logo upload", func(r *CreateCheckRequest) { r.Logo = FileUpload("logo.JPG", strings.NewReader("jpeg")) }, nil},
+		{"logo upload not an image", func(r *CreateCheckRequest) { r.Logo = FileUpload("logo.gif", strings.NewReader("gif")) }, []string{"logo"}},
+		{"logo HTML", func(r *CreateCheckRequest) { r.Logo = FileHTML("<img>") }, []string{"logo"}},
+		{"empty logo", func(r *CreateCheckRequest) { r.Logo = &File{} }, []string{"logo"}},
+		{"attachment URL", func(r *CreateCheckRequest) { r.Attachment = FileURL("https://example.com/invoice.pdf") }, nil},
+		{"attachment HTML", func(r *CreateCheckRequest) { r.Attachment = FileHTML("<h1>Invoice</h1>") }, nil},
+		{"attachment upload", func(r *CreateCheckRequest) { r.Attachment = FileUpload("invoice.pdf", strings.NewReader("%PDF")) }, nil},
+		{"attachment upload not a PDF", func(r *CreateCheckRequest) { r.Attachment = FileUpload("invoice.docx", strings.NewReader("")) }, []string{"attachment"}},
+		{"unknown mail type", func(r *CreateCheckRequest) { r.MailType = nullString("carrier_pigeon") }, []string{"mail_type"}},
+		{"bad check number", func(r *CreateCheckRequest) { r.CheckNumber = nullString("12a") }, []string{"check_number"}},
+		{"send date in the past", func(r *CreateCheckRequest) {
+			past := now.Add(-time.Hour)
+			r.SendDate = &past
+		}, []string{"send_date"}},
+		{"send date within limit", func(r *CreateCheckRequest) {
+			later := now.Add(MaxSendDateLead)
+			r.SendDate = &later
+		}, nil},
+		{"send date too far ahead", func(r *CreateCheckRequest) {
+			later := now.Add(MaxSendDateLead + time.Hour)
+			r.SendDate = &later
+		}, []string{"send_date"}},
+	}
+
+	for _, test := range tests {
+		req := valid()
+		test.modify(req)
+		expectFieldErrors(t, test.name, req.validate(now), test.fields)
+	}
+}
+
+func TestFakeLobCreateCheckValidates(t *testing.T) {
+	check, err := NewFakeLob().CreateCheck(&CreateCheckRequest{
+		Amount: MustParseMoney("10"),
+		Memo:   nullString(strings.Repeat("m", 41)),
+	})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	if _, ok := errs.Field("memo"); !ok {
+		t.Errorf("Expected a memo error, got %s", errs)
+	}
+	if check.Error == nil || check.Error.StatusCode != 422 {
+		t.Errorf("Expected a 422 error on the check, got %+v", check.Error)
+	}
+}
+
+func TestCheckCancellable(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		check       Check
+		cancellable bool
+	}{
+		{"before send date", Check{SendDate: Timestamp{now.Add(time.Minute)}}, true},
+		{"at send date", Check{SendDate: Timestamp{now}}, false},
+		{"after send date", Check{SendDate: Timestamp{now.Add(-time.Minute)}}, false},
+		{"deleted", Check{SendDate: Timestamp{now.Add(time.Hour)}, Deleted: true}, false},
+		{"no send date", Check{}, false},
+	}
+	for _, test := range tests {
+		if got := test.check.Cancellable(now); got != test.cancellable {
+			t.Errorf("%s: expected cancellable %v, got %v", test.name, test.cancellable, got)
+		}
+	}
+}
+
+func TestFakeLobCancellationWindow(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	fake := NewFakeLob()
+	fake.Now = func() time.Time { return now }
+	fake.CancellationWindow = 5 * time.Minute
+
+	address, err := fake.CreateAddress(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bankAccount, err := fake.CreateBankAccount(&CreateBankAccountRequest{
+		RoutingNumber: "255077370",
+		AccountNumber: "1234",
+		Signatory:     "Lobster Test",
+		AccountType:   AccountTypeCompany,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := func(sendDate *time.Time) *Check {
+		check, err := fake.CreateCheck(&CreateCheckRequest{
+			Amount:        MustParseMoney("10"),
+			BankAccountID: bankAccount.ID,
+			From:          AddressID(address.ID),
+			To:            AddressID(address.ID),
+			SendDate:      sendDate,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return check
+	}
+
+	immediate := create(nil)
+	if !immediate.SendDate.Equal(now.Add(5 * time.Minute)) {
+		t.Errorf("Expected the send date to be the end of the cancellation window, got %s", immediate.SendDate)
+	}
+	scheduledFor := now.Add(72 * time.Hour)
+	scheduled := create(&scheduledFor)
+
+	now = now.Add(10 * time.Minute)
+	var apiErr *APIError
+	if _, err := fake.CancelCheck(immediate.ID); !errors.Is(err, ErrCheckNotCancellable) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected a sent check not to be cancellable, got %v", err)
+	}
+	resp, err := fake.CancelCheck(scheduled.ID)
+	if err != nil || !resp.Deleted {
+		t.Errorf("Expected the scheduled check to be cancelled, got %+v (%v)", resp, err)
+	}
+}
+
+func TestCheckJSON(t *testing.T) {
+	data := `{
+		"id": "chk_534f10783683daa0",
+		"description": "Demo Check",
+		"check_number": 10062,
+		"amount": 22.5,
+		"carrier": "USPS",
+		"check_bottom_template_id": "tmpl_a6b27fdf41d1b86",
+		"attachment": "https://lob-assets.com/checks/attachment.pdf",
+		"merge_variables": {"name": "Harry"},
+		"thumbnails": [{"small": "https://lob.com/s.png", "medium": "https://lob.com/m.png", "large": "https://lob.com/l.png"}],
+		"tracking_events": [{"id": "evnt_9e84094c9368cfb", "name": "In Transit", "type": "normal", "location": "72231", "time": "2017-09-07T14:21:30.000Z", "object": "tracking_event"}],
+		"expected_delivery_date": "2017-09-11",
+		"send_date": "2017-09-05T17:47:53.767Z",
+		"date_created": "2017-09-05T17:47:53.767Z",
+		"date_modified": "2017-09-05T17:47:53.767Z",
+		"deleted": false,
+		"object": "check"
+	}`
+	var check Check
+	if err := json.Unmarshal([]byte(data), &check); err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2017, 9, 5, 17, 47, 53, 767000000, time.UTC)
+	if !check.DateCreated.Equal(created) || !check.DateModified.Equal(created) || !check.SendDate.Equal(created) {
+		t.Errorf("Expected timestamps of %s, got %+v", created, check)
+	}
+	if check.ExpectedDeliveryDate != (Date{2017, time.September, 11}) {
+		t.Errorf("Expected delivery on 2017-09-11, got %s", check.ExpectedDeliveryDate)
+	}
+	if check.Carrier != "USPS" || check.CheckBottomTemplateID == nil || *check.CheckBottomTemplateID != "tmpl_a6b27fdf41d1b86" {
+		t.Errorf("Expected carrier and template, got %+v", check)
+	}
+	if check.Attachment == nil || check.Attachment.URL != "https://lob-assets.com/checks/attachment.pdf" {
+		t.Errorf("Expected an attachment URL, got %+v", check.Attachment)
+	}
+	if check.MergeVariables["name"] != "Harry" {
+		t.Errorf("Expected merge variables, got %v", check.MergeVariables)
+	}
+	if len(check.Thumbnails) != 1 || check.Thumbnails[0].Large != "https://lob.com/l.png" {
+		t.Errorf("Expected typed thumbnails, got %v", check.Thumbnails)
+	}
+	if len(check.TrackingEvents) != 1 || check.TrackingEvents[0].Name != "In Transit" ||
+		!check.TrackingEvents[0].Time.Equal(time.Date(2017, 9, 7, 14, 21, 30, 0, time.UTC)) {
+		t.Errorf("Expected a tracking event, got %+v", check.TrackingEvents)
+	}
+
+	var unscheduled Check
+	if err := json.Unmarshal([]byte(`{"send_date": "", "expected_delivery_date": null}`), &unscheduled); err != nil {
+		t.Fatal(err)
+	}
+	if !unscheduled.SendDate.IsZero() || !unscheduled.ExpectedDeliveryDate.IsZero() {
+		t.Errorf("Expected empty dates to decode as zero, got %+v", unscheduled)
+	}
+}
@@ -0,0 +1,141 @@
+package lob
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials supplies the client's API keys, so that keys can live in the environment, a
+// secrets file or a secrets manager rather than in the client itself. account is the name
+// passed to WithAccount, or "" for the client's default account. Implementations must be safe
+// for concurrent use.
+type Credentials interface {
+	APIKey(account string, env Environment) (string, error)
+}
+
+// CredentialsFunc adapts a function, such as a call to a secrets manager, to Credentials.
+type CredentialsFunc func(account string, env Environment) (string, error)
+
+// APIKey calls f.
+func (f CredentialsFunc) APIKey(account string, env Environment) (string, error) {
+	return f(account, env)
+}
+
+// EnvCredentials reads keys from environment variables named prefix, then the upper cased
+// account name and an underscore for named accounts, then TEST_API_KEY or LIVE_API_KEY. With
+// the prefix "LOB_", the default account's test key is read from LOB_TEST_API_KEY and the
+// "payroll" account's live key from LOB_PAYROLL_LIVE_API_KEY. Variables are read on every
+// request, so keys can be changed without restarting.
+func EnvCredentials(prefix string) Credentials {
+	return CredentialsFunc(func(account string, env Environment) (string, error) {
+		name := prefix
+		if account != "" {
+			name += strings.ToUpper(account) + "_"
+		}
+		name += strings.ToUpper(string(env)) + "_API_KEY"
+		key := os.Getenv(name)
+		if key == "" {
+			return "", fmt.Errorf("%w: %s is not set", ErrNoAPIKey, name)
+		}
+		return key, nil
+	})
+}
+
+// credentialsFile is the format read by FileCredentials.
+type credentialsFile struct {
+	Account
+	Accounts map[string]Account `json:"accounts"`
+}
+
+// FileCredentials reads keys from a JSON file such as
+//
+//	{
+//		"test_key": "test_...",
+//		"live_key": "live_...",
+//		"accounts": {"payroll": {"test_key": "test_...", "live_key": "live_..."}}
+//	}
+//
+// where the top level keys belong to the default account. The file is read on every request,
+// so rotated keys are picked up as soon as the file is replaced.
+func FileCredentials(path string) Credentials {
+	return CredentialsFunc(func(account string, env Environment) (string, error) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		var file credentialsFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return "", fmt.Errorf("reading Lob credentials from %s: %w", path, err)
+		}
+		keys := file.Account
+		if account != "" {
+			var ok bool
+			if keys, ok = file.Accounts[account]; !ok {
+				return "", fmt.Errorf("%w: %q", ErrUnknownAccount, account)
+			}
+		}
+		return keys.Key(env), nil
+	})
+}
+
+// RotatingCredentials caches keys from another source and fetches them again once they are
+// older than a refresh interval or after Rotate is called, so keys can be rotated in the source
+// without fetching them for every request.
+type RotatingCredentials struct {
+	source  Credentials
+	refresh time.Duration
+	now     func() time.Time
+
+	mu   sync.Mutex
+	keys map[credentialsKey]cachedKey
+}
+
+type credentialsKey struct {
+	account string
+	env     Environment
+}
+
+type cachedKey struct {
+	key     string
+	fetched time.Time
+}
+
+// NewRotatingCredentials caches keys from source for up to refresh.
+func NewRotatingCredentials(source Credentials, refresh time.Duration) *RotatingCredentials {
+	return &RotatingCredentials{
+		source:  source,
+		refresh: refresh,
+		now:     time.Now,
+		keys:    make(map[credentialsKey]cachedKey),
+	}
+}
+
+// APIKey returns the cached key, fetching it from the source if it is missing or stale.
+func (r *RotatingCredentials) APIKey(account string, env Environment) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := credentialsKey{account: account, env: env}
+	now := r.now()
+	if cached, ok := r.keys[k]; ok && now.Sub(cached.fetched) < r.refresh {
+		return cached.key, nil
+	}
+	key, err := r.source.APIKey(account, env)
+	if err != nil {
+		return "", err
+	}
+	r.keys[k] = cachedKey{key: key, fetched: now}
+	return key, nil
+}
+
+// Rotate discards every cached key, so the next request fetches keys from the source.
+func (r *RotatingCredentials) Rotate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = make(map[credentialsKey]cachedKey)
+}
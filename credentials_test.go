@@ -0,0 +1,108 @@
+package lob
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("LOB_TEST_API_KEY", "test_default")
+	t.Setenv("LOB_PAYROLL_LIVE_API_KEY", "live_payroll")
+	creds := EnvCredentials("LOB_")
+
+	if key, err := creds.APIKey("", EnvironmentTest); err != nil || key != "test_default" {
+		t.Errorf("Expected the default test key, got %q, %v", key, err)
+	}
+	if key, err := creds.APIKey("payroll", EnvironmentLive); err != nil || key != "live_payroll" {
+		t.Errorf("Expected the payroll live key, got %q, %v", key, err)
+	}
+	if _, err := creds.APIKey("payroll", EnvironmentTest); !errors.Is(err, ErrNoAPIKey) {
+		t.Errorf("Expected ErrNoAPIKey for an unset variable, got %v", err)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lob.json")
+	write := func(data string) {
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"test_key": "test_default", "accounts": {"payroll": {"test_key": "test_payroll"}}}`)
+	creds := FileCredentials(path)
+
+	if key, err := creds.APIKey("", EnvironmentTest); err != nil || key != "test_default" {
+		t.Errorf("Expected the default test key, got %q, %v", key, err)
+	}
+	if key, err := creds.APIKey("payroll", EnvironmentTest); err != nil || key != "test_payroll" {
+		t.Errorf("Expected the payroll test key, got %q, %v", key, err)
+	}
+	if _, err := creds.APIKey("acme", EnvironmentTest); !errors.Is(err, ErrUnknownAccount) {
+		t.Errorf("Expected ErrUnknownAccount, got %v", err)
+	}
+
+	write(`{"test_key": "test_rotated"}`)
+	if key, _ := creds.APIKey("", EnvironmentTest); key != "test_rotated" {
+		t.Errorf("Expected the rotated key to be read, got %q", key)
+	}
+}
+
+func TestRotatingCredentials(t *testing.T) {
+	fetches := 0
+	current := "test_first"
+	creds := NewRotatingCredentials(CredentialsFunc(func(account string, env Environment) (string, error) {
+		fetches++
+		return current, nil
+	}), time.Hour)
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	creds.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if key, _ := creds.APIKey("", EnvironmentTest); key != "test_first" {
+			t.Errorf("Expected the cached key, got %q", key)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected one fetch while the key is fresh, got %d", fetches)
+	}
+
+	current = "test_second"
+	now = now.Add(time.Hour)
+	if key, _ := creds.APIKey("", EnvironmentTest); key != "test_second" {
+		t.Errorf("Expected the key to be fetched again once stale, got %q", key)
+	}
+
+	current = "test_third"
+	creds.Rotate()
+	if key, _ := creds.APIKey("", EnvironmentTest); key != "test_third" {
+		t.Errorf("Expected the key to be fetched again after Rotate, got %q", key)
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	var usedKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usedKey, _, _ = r.BasicAuth()
+		w.Write([]byte(`{"id": "adr_123"}`))
+	}))
+	defer server.Close()
+
+	l := NewLob(server.URL+"/", "", testUserAgent)
+	l.Credentials = CredentialsFunc(func(account string, env Environment) (string, error) {
+		return string(env) + "_" + account, nil
+	})
+	if _, err := l.WithAccount("payroll").GetAddress("adr_123"); err != nil {
+		t.Fatal(err)
+	}
+	if usedKey != "test_payroll" {
+		t.Errorf("Expected the provider's key, got %q", usedKey)
+	}
+	if _, err := l.WithEnvironment(EnvironmentLive).GetAddress("adr_123"); !errors.Is(err, ErrLiveKeyNotAllowed) {
+		t.Errorf("Expected provided live keys to be guarded too, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package lob
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timestampLayout is the format Lob uses for timestamps, e.g. 2017-09-05T17:47:53.767Z.
+const timestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// dateLayout is the format Lob uses for calendar dates, e.g. 2017-09-08.
+const dateLayout = "2006-01-02"
+
+// Timestamp is a point in time that Lob may send as a full timestamp, a bare date, an empty
+// string or null. The empty forms decode to the zero Timestamp, and bare dates to midnight UTC.
+type Timestamp struct {
+	time.Time
+}
+
+// MarshalJSON encodes the timestamp in Lob's format, or as null if it is zero.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(t.UTC().Format(timestampLayout))), nil
+}
+
+// UnmarshalJSON decodes any of the forms Lob sends.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	s, err := unquoteDate(data)
+	if err != nil || s == "" {
+		*t = Timestamp{}
+		return err
+	}
+	if parsed, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		t.Time = parsed
+		return nil
+	}
+	parsed, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", s)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// Date is a calendar date with no time of day, such as a check's expected delivery date.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date on which t falls, in t's location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses a date in Lob's format, e.g. "2017-09-08".
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q", s)
+	}
+	return DateOf(t), nil
+}
+
+// String formats the date in Lob's format, e.g. "2017-09-08".
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero reports whether the date is unset.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns midnight at the start of the date in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// AddDays returns the date n days after d.
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.In(time.UTC).AddDate(0, 0, n))
+}
+
+// Before reports whether d is earlier than e.
+func (d Date) Before(e Date) bool {
+	return d.In(time.UTC).Before(e.In(time.UTC))
+}
+
+// After reports whether d is later than e.
+func (d Date) After(e Date) bool {
+	return e.Before(d)
+}
+
+// MarshalJSON encodes the date in Lob's format, or as null if it is zero.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decodes a date, or the date part of a timestamp. Empty strings and null decode
+// to the zero Date.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s, err := unquoteDate(data)
+	if err != nil || s == "" {
+		*d = Date{}
+		return err
+	}
+	if len(s) > len(dateLayout) {
+		s = s[:len(dateLayout)]
+	}
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// unquoteDate returns the string in a JSON date value, or "" for null.
+func unquoteDate(data []byte) (string, error) {
+	if string(data) == "null" {
+		return "", nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return "", fmt.Errorf("invalid date %s", data)
+	}
+	return s, nil
+}
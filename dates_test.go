@@ -0,0 +1,86 @@
+package lob
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampJSON(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected time.Time
+	}{
+		{`"2017-09-05T17:47:53.767Z"`, time.Date(2017, 9, 5, 17, 47, 53, 767000000, time.UTC)},
+		{`"2017-09-05T17:47:53Z"`, time.Date(2017, 9, 5, 17, 47, 53, 0, time.UTC)},
+		{`"2017-09-08"`, time.Date(2017, 9, 8, 0, 0, 0, 0, time.UTC)},
+		{`""`, time.Time{}},
+		{`null`, time.Time{}},
+	}
+	for _, test := range tests {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(test.json), &ts); err != nil {
+			t.Errorf("%s: %v", test.json, err)
+			continue
+		}
+		if !ts.Equal(test.expected) {
+			t.Errorf("%s: expected %s, got %s", test.json, test.expected, ts)
+		}
+	}
+
+	var ts Timestamp
+	if err := json.Unmarshal([]byte(`"next tuesday"`), &ts); err == nil {
+		t.Error("Expected an error for an invalid timestamp")
+	}
+
+	data, _ := json.Marshal(Timestamp{time.Date(2017, 9, 5, 10, 47, 53, 767000000, time.FixedZone("PDT", -7*3600))})
+	if string(data) != `"2017-09-05T17:47:53.767Z"` {
+		t.Errorf("Expected Lob's UTC format, got %s", data)
+	}
+	if data, _ := json.Marshal(Timestamp{}); string(data) != "null" {
+		t.Errorf("Expected a zero timestamp to encode as null, got %s", data)
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected Date
+	}{
+		{`"2017-09-08"`, Date{2017, time.September, 8}},
+		{`"2017-09-08T00:00:00.000Z"`, Date{2017, time.September, 8}},
+		{`""`, Date{}},
+		{`null`, Date{}},
+	}
+	for _, test := range tests {
+		var d Date
+		if err := json.Unmarshal([]byte(test.json), &d); err != nil {
+			t.Errorf("%s: %v", test.json, err)
+			continue
+		}
+		if d != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.json, test.expected, d)
+		}
+	}
+
+	var d Date
+	if err := json.Unmarshal([]byte(`"9/8/2017"`), &d); err == nil {
+		t.Error("Expected an error for a date in the wrong format")
+	}
+	if data, _ := json.Marshal(Date{2017, time.September, 8}); string(data) != `"2017-09-08"` {
+		t.Errorf("Expected Lob's date format, got %s", data)
+	}
+}
+
+func TestDateArithmetic(t *testing.T) {
+	d := Date{2019, time.December, 31}
+	if next := d.AddDays(1); next != (Date{2020, time.January, 1}) {
+		t.Errorf("Expected the next day to be 2020-01-01, got %s", next)
+	}
+	if !d.Before(d.AddDays(1)) || d.After(d.AddDays(1)) || d.Before(d) {
+		t.Error("Expected dates to be ordered by day")
+	}
+	if parsed, err := ParseDate("2019-12-31"); err != nil || parsed != d {
+		t.Errorf("Expected to parse 2019-12-31, got %s, %v", parsed, err)
+	}
+}
@@ -0,0 +1,218 @@
+package lob
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONBody(t *testing.T) {
+	body, err := jsonBody(&CreateCheckRequest{
+		Amount:        MustParseMoney("987.65"),
+		BankAccountID: "bank_123",
+		From:          AddressID("adr_123"),
+		To: InlineAddress(&Address{
+			Name:         nullString("Lobster Test"),
+			AddressLine1: "1005 W Burnside St",
+			AddressZip:   nullString("97209"),
+			Metadata:     map[string]string{"customer": "42"},
+		}),
+		Memo: nullString("A memo"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body.contentType != "application/json" {
+		t.Errorf("Expected a JSON content type, got %s", body.contentType)
+	}
+	data, err := ioutil.ReadAll(body.reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"amount":       987.65,
+		"bank_account": "bank_123",
+		"from":         "adr_123",
+		"to": map[string]interface{}{
+			"name":          "Lobster Test",
+			"address_line1": "1005 W Burnside St",
+			"address_zip":   "97209",
+			"metadata":      map[string]interface{}{"customer": "42"},
+		},
+		"memo": "A memo",
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Expected body %v, got %s", expected, data)
+	}
+
+	var roundTrip CreateCheckRequest
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if roundTrip.From.ID != "adr_123" || roundTrip.To.Address == nil || roundTrip.To.Address.AddressLine1 != "1005 W Burnside St" {
+		t.Errorf("Expected address references to round trip, got %+v", roundTrip)
+	}
+}
+
+func TestMultipartBody(t *testing.T) {
+	req := &CreateCheckRequest{
+		Amount:        MustParseMoney("987.65"),
+		BankAccountID: "bank_123",
+		From:          AddressID("adr_123"),
+		To:            AddressID("adr_456"),
+		Logo:          FileURL("https://example.com/logo.png"),
+		Attachment:    FileUpload("/tmp/invoice.pdf", strings.NewReader("%PDF-1.4 invoice")),
+	}
+	body, err := multipartBody(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(body.contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("Expected a multipart content type, got %s", body.contentType)
+	}
+	form, err := multipart.NewReader(body.reader, params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"amount":       {"987.65"},
+		"bank_account": {"bank_123"},
+		"from":         {"adr_123"},
+		"to":           {"adr_456"},
+		"logo":         {"https://example.com/logo.png"},
+	}
+	if !reflect.DeepEqual(form.Value, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, form.Value)
+	}
+	files := form.File["attachment"]
+	if len(files) != 1 {
+		t.Fatalf("Expected one attachment, got %d", len(files))
+	}
+	if files[0].Filename != "invoice.pdf" || files[0].Header.Get("Content-Type") != "application/pdf" {
+		t.Errorf("Expected invoice.pdf as application/pdf, got %s as %s", files[0].Filename, files[0].Header.Get("Content-Type"))
+	}
+	f, err := files[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if data, _ := ioutil.ReadAll(f); string(data) != "%PDF-1.4 invoice" {
+		t.Errorf("Expected the attachment contents to be uploaded, got %q", data)
+	}
+}
+
+func TestPostChoosesEncoding(t *testing.T) {
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		w.Write([]byte(`{"id": "chk_123"}`))
+	}))
+	defer server.Close()
+	l := NewLob(server.URL+"/", "test_key", testUserAgent)
+
+	req := &CreateCheckRequest{
+		Amount:        MustParseMoney("10"),
+		BankAccountID: "bank_123",
+		From:          AddressID("adr_123"),
+		To:            AddressID("adr_456"),
+		Logo:          FileURL("https://example.com/logo.png"),
+	}
+	if _, err := l.CreateCheck(req); err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Expected a JSON body without uploads, got %s", contentType)
+	}
+
+	req.Logo = FileUpload("logo.png", strings.NewReader("png"))
+	if _, err := l.CreateCheck(req); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(contentType, "multipart/form-data") {
+		t.Errorf("Expected a multipart body with uploads, got %s", contentType)
+	}
+}
+
+func TestMultipartBodyStreamsFilePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invoice.pdf")
+	if err := ioutil.WriteFile(path, []byte("%PDF-1.4 from disk"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var uploaded, filename string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("attachment")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer file.Close()
+		data, _ := ioutil.ReadAll(file)
+		uploaded, filename = string(data), header.Filename
+		w.Write([]byte(`{"id": "chk_123"}`))
+	}))
+	defer server.Close()
+	l := NewLob(server.URL+"/", "test_key", testUserAgent)
+
+	_, err := l.CreateCheck(&CreateCheckRequest{
+		Amount:        MustParseMoney("10"),
+		BankAccountID: "bank_123",
+		From:          AddressID("adr_123"),
+		To:            AddressID("adr_456"),
+		Attachment:    FilePath(path),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploaded != "%PDF-1.4 from disk" || filename != "invoice.pdf" {
+		t.Errorf("Expected invoice.pdf to be uploaded from disk, got %q named %q", uploaded, filename)
+	}
+}
+
+type failingReader struct{}
+
+var errReadFailed = errors.New("read failed")
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errReadFailed
+}
+
+func TestMultipartBodyReadError(t *testing.T) {
+	body, err := multipartBody(&CreateCheckRequest{
+		Attachment: FileUpload("invoice.pdf", failingReader{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(ioutil.Discard, body.reader); !errors.Is(err, errReadFailed) {
+		t.Errorf("Expected the reader's error, got %v", err)
+	}
+
+	body, err = multipartBody(&CreateCheckRequest{
+		Attachment: FilePath(filepath.Join(t.TempDir(), "missing.pdf")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(ioutil.Discard, body.reader); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a missing file error, got %v", err)
+	}
+}
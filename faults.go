@@ -0,0 +1,136 @@
+package lob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AnyEndpoint names every endpoint when injecting faults.
+const AnyEndpoint = "*"
+
+// Fault is a failure injected into a call to the fake or the lobtest simulator. A fault with a
+// status code fails the call before Lob acts on it. Timeouts and malformed responses happen after
+// Lob has acted on the call, when only its response is lost, which is the case retries must be
+// idempotent for.
+type Fault struct {
+	// StatusCode fails the call with this status, e.g. 429 or 503.
+	StatusCode int
+	// Message is the error message sent with StatusCode. It defaults to the status text.
+	Message string
+	// Latency delays the response.
+	Latency time.Duration
+	// Timeout makes the call time out instead of responding.
+	Timeout bool
+	// Malformed truncates the response body, so it is not valid JSON.
+	Malformed bool
+}
+
+// IsZero reports whether the fault injects nothing.
+func (f Fault) IsZero() bool {
+	return f == Fault{}
+}
+
+// ErrorMessage returns the message the fault's status code is sent with.
+func (f Fault) ErrorMessage() string {
+	if f.Message != "" {
+		return f.Message
+	}
+	return http.StatusText(f.StatusCode)
+}
+
+// apiError returns the error the client returns for the fault's status code.
+func (f Fault) apiError(endpoint string) error {
+	return newFakeError(endpoint, f.StatusCode, f.ErrorMessage())
+}
+
+// lostResponseError returns the error the client returns when the response to a call it made is
+// lost to a timeout or a malformed body, or nil if it isn't.
+func (f Fault) lostResponseError(endpoint string) error {
+	switch {
+	case f.Timeout:
+		return fmt.Errorf("%s: %w", endpoint, context.DeadlineExceeded)
+	case f.Malformed:
+		var v interface{}
+		return json.Unmarshal([]byte(`{"id":`), &v)
+	}
+	return nil
+}
+
+type faultRate struct {
+	rate  float64
+	fault Fault
+}
+
+// Faults decides which calls fail and how. Scripted faults are used first, in order, then faults
+// injected at random rates. Endpoints are named after the Lob methods, e.g. "CreateCheck", or
+// AnyEndpoint. A nil *Faults injects nothing. Faults is safe for concurrent use.
+type Faults struct {
+	mu       sync.Mutex
+	rand     *rand.Rand
+	scripted map[string][]Fault
+	rates    map[string][]faultRate
+}
+
+// NewFaults returns Faults that inject nothing until told to. Its random faults are seeded, so a
+// test sees the same failures on every run.
+func NewFaults() *Faults {
+	return &Faults{
+		rand:     rand.New(rand.NewSource(1)),
+		scripted: make(map[string][]Fault),
+		rates:    make(map[string][]faultRate),
+	}
+}
+
+// FailNext injects the fault into the next n calls to the endpoint, e.g. to fail the next two
+// CreateCheck calls with a 503.
+func (f *Faults) FailNext(endpoint string, n int, fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := 0; i < n; i++ {
+		f.scripted[endpoint] = append(f.scripted[endpoint], fault)
+	}
+}
+
+// SetRate injects the fault into the given fraction of calls to the endpoint, from 0 to 1. A rate
+// of 1 with only a Latency slows every call down.
+func (f *Faults) SetRate(endpoint string, rate float64, fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rates[endpoint] = append(f.rates[endpoint], faultRate{rate: rate, fault: fault})
+}
+
+// Reset removes every fault.
+func (f *Faults) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scripted = make(map[string][]Fault)
+	f.rates = make(map[string][]faultRate)
+}
+
+// Next returns the fault to inject into a call to the endpoint, or the zero Fault.
+func (f *Faults) Next(endpoint string) Fault {
+	if f == nil {
+		return Fault{}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, e := range []string{endpoint, AnyEndpoint} {
+		if scripted := f.scripted[e]; len(scripted) > 0 {
+			f.scripted[e] = scripted[1:]
+			return scripted[0]
+		}
+	}
+	for _, e := range []string{endpoint, AnyEndpoint} {
+		for _, r := range f.rates[e] {
+			if f.rand.Float64() < r.rate {
+				return r.fault
+			}
+		}
+	}
+	return Fault{}
+}
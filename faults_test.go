@@ -0,0 +1,97 @@
+package lob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFaultsNext(t *testing.T) {
+	var none *Faults
+	if fault := none.Next("CreateCheck"); !fault.IsZero() {
+		t.Errorf("Expected nil faults to inject nothing, got %+v", fault)
+	}
+
+	faults := NewFaults()
+	unavailable := Fault{StatusCode: http.StatusServiceUnavailable}
+	slow := Fault{Latency: time.Second}
+	faults.FailNext("CreateCheck", 2, unavailable)
+	faults.FailNext(AnyEndpoint, 1, slow)
+
+	for i, expected := range []Fault{unavailable, unavailable, slow, {}} {
+		if fault := faults.Next("CreateCheck"); fault != expected {
+			t.Errorf("Call %d: expected %+v, got %+v", i, expected, fault)
+		}
+	}
+	if fault := faults.Next("GetCheck"); !fault.IsZero() {
+		t.Errorf("Expected other endpoints not to fail, got %+v", fault)
+	}
+
+	faults.SetRate("GetCheck", 0.5, unavailable)
+	failed := 0
+	for i := 0; i < 1000; i++ {
+		if !faults.Next("GetCheck").IsZero() {
+			failed++
+		}
+	}
+	if failed < 400 || failed > 600 {
+		t.Errorf("Expected about half of the calls to fail, got %d of 1000", failed)
+	}
+
+	faults.Reset()
+	if fault := faults.Next("GetCheck"); !fault.IsZero() {
+		t.Errorf("Expected no faults after a reset, got %+v", fault)
+	}
+}
+
+func TestFakeLobFaults(t *testing.T) {
+	fake := NewFakeLob()
+	fake.Faults = NewFaults()
+	bankAccount, err := fake.CreateBankAccount(&CreateBankAccountRequest{
+		AccountNumber: "1132234455",
+		RoutingNumber: "255077370",
+		Signatory:     "Big Bird",
+		AccountType:   AccountTypeCompany,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := *testAddress
+	address.ID = ""
+	req := &CreateCheckRequest{
+		Amount:        MustParseMoney("100.00"),
+		BankAccountID: bankAccount.ID,
+		From:          InlineAddress(&address),
+		To:            InlineAddress(&address),
+	}
+
+	fake.Faults.FailNext("CreateCheck", 2, Fault{StatusCode: http.StatusServiceUnavailable})
+	for i := 0; i < 2; i++ {
+		var apiErr *APIError
+		if _, err := fake.CreateCheck(req); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected a 503, got %v", err)
+		}
+	}
+	if len(fake.checks) != 0 {
+		t.Errorf("Expected failed calls not to create checks, got %d", len(fake.checks))
+	}
+	if _, err := fake.CreateCheck(req); err != nil {
+		t.Errorf("Expected the third call to succeed, got %v", err)
+	}
+
+	fake.Faults.FailNext("CreateCheck", 1, Fault{Timeout: true})
+	if _, err := fake.CreateCheck(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+	fake.Faults.FailNext("CreateCheck", 1, Fault{Malformed: true})
+	var syntaxErr *json.SyntaxError
+	if _, err := fake.CreateCheck(req); !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected a JSON syntax error, got %v", err)
+	}
+	if len(fake.checks) != 3 {
+		t.Errorf("Expected lost responses to still create checks, got %d", len(fake.checks))
+	}
+}
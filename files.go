@@ -0,0 +1,195 @@
+package lob
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// File is a document or image sent to Lob with a request. Lob accepts a URL it can fetch, an
+// HTML string it renders, or the contents of a local file, which are uploaded with the request
+// as multipart/form-data. Exactly one of URL, HTML and Reader should be set, or the File should
+// come from FilePath.
+//
+// Request structs declare file fields with the *File type; any request with an upload in such a
+// field is sent as multipart/form-data, and the upload is streamed from its reader as the
+// request is sent rather than read into memory first.
+type File struct {
+	URL  string
+	HTML string
+	// Name is the file name of an upload; its extension tells Lob what kind of file it is.
+	Name   string
+	Reader io.Reader
+
+	path string
+}
+
+// FileURL refers to a file Lob fetches from url.
+func FileURL(url string) *File {
+	return &File{URL: url}
+}
+
+// FileHTML refers to an HTML document Lob renders.
+func FileHTML(html string) *File {
+	return &File{HTML: html}
+}
+
+// FileUpload refers to a local file whose contents are read from r and uploaded to Lob.
+func FileUpload(name string, r io.Reader) *File {
+	return &File{Name: name, Reader: r}
+}
+
+// FilePath refers to the local file at path, which is opened when the request is sent and
+// uploaded to Lob.
+func FilePath(path string) *File {
+	return &File{Name: filepath.Base(path), path: path}
+}
+
+// IsUpload reports whether the file's contents are uploaded with the request.
+func (f *File) IsUpload() bool {
+	return f != nil && (f.Reader != nil || f.path != "")
+}
+
+// open returns the contents of an upload.
+func (f *File) open() (io.ReadCloser, error) {
+	if f.path != "" {
+		return os.Open(f.path)
+	}
+	return ioutil.NopCloser(f.Reader), nil
+}
+
+// MarshalJSON encodes a URL or HTML file as a string. Uploads cannot be part of a JSON body and
+// encode as null; requests with uploads are sent as multipart/form-data instead.
+func (f *File) MarshalJSON() ([]byte, error) {
+	switch {
+	case f.IsUpload():
+		return []byte("null"), nil
+	case f.URL != "":
+		return json.Marshal(f.URL)
+	default:
+		return json.Marshal(f.HTML)
+	}
+}
+
+// UnmarshalJSON decodes a URL or HTML string.
+func (f *File) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*f = File{}
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		f.URL = s
+	} else {
+		f.HTML = s
+	}
+	return nil
+}
+
+// fileUpload is a file to upload along with the name of the request field it belongs to.
+type fileUpload struct {
+	field string
+	file  *File
+}
+
+var fileType = reflect.TypeOf((*File)(nil))
+
+// fileUploads returns the uploads among the top level *File fields of the request struct v.
+func fileUploads(v interface{}) []fileUpload {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	var uploads []fileUpload
+	for i := 0; i < value.NumField(); i++ {
+		f := value.Type().Field(i)
+		if f.Type != fileType {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if file := value.Field(i).Interface().(*File); file.IsUpload() {
+			uploads = append(uploads, fileUpload{field: name, file: file})
+		}
+	}
+	return uploads
+}
+
+// multipartBody encodes v as a multipart/form-data request body: its file uploads as file parts
+// and everything else as form fields named the way json2form names them. The body is written
+// by a goroutine as the request reads it, so uploads are never held in memory.
+func multipartBody(v interface{}) (*requestBody, error) {
+	params, err := json2form(v)
+	if err != nil {
+		return nil, err
+	}
+	uploads := fileUploads(v)
+
+	reader, pipe := io.Pipe()
+	writer := multipart.NewWriter(pipe)
+	go func() {
+		pipe.CloseWithError(writeMultipart(writer, params, uploads))
+	}()
+
+	return &requestBody{
+		contentType: writer.FormDataContentType(),
+		reader:      reader,
+	}, nil
+}
+
+func writeMultipart(writer *multipart.Writer, params map[string]string, uploads []fileUpload) error {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := writer.WriteField(k, params[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, upload := range uploads {
+		part, err := writer.CreatePart(filePartHeader(upload))
+		if err != nil {
+			return err
+		}
+		contents, err := upload.file.open()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(part, contents)
+		contents.Close()
+		if err != nil {
+			return fmt.Errorf("uploading %s: %w", upload.field, err)
+		}
+	}
+	return writer.Close()
+}
+
+// filePartHeader returns the MIME header for an uploaded file, typed by its extension.
+func filePartHeader(upload fileUpload) textproto.MIMEHeader {
+	contentType := mime.TypeByExtension(path.Ext(upload.file.Name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(upload.field), quoteEscaper.Replace(path.Base(upload.file.Name))))
+	header.Set("Content-Type", contentType)
+	return header
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
@@ -0,0 +1,60 @@
+package lob
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// json2form uses JSON tag information to create a form values map, for endpoints that take form
+// encoded bodies rather than JSON. v is encoded exactly as it would be for a JSON body, so tag
+// options such as omitempty and "-", explicit zero values, time.Time and custom JSON marshalers
+// behave the same way. Nested objects and arrays are flattened into keys such as
+// "to[address_line1]" and "items[0][name]". Null values are left out, since a form cannot
+// express them.
+func json2form(v interface{}) (map[string]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keep numbers exactly as they were encoded
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	object, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot form encode %T: it does not encode as a JSON object", v)
+	}
+
+	params := make(map[string]string)
+	for key, value := range object {
+		flattenForm(params, key, value)
+	}
+	return params, nil
+}
+
+// flattenForm adds the decoded JSON value to params under key.
+func flattenForm(params map[string]string, key string, value interface{}) {
+	switch x := value.(type) {
+	case map[string]interface{}:
+		for k, v := range x {
+			flattenForm(params, key+"["+k+"]", v)
+		}
+	case []interface{}:
+		for i, v := range x {
+			flattenForm(params, key+"["+strconv.Itoa(i)+"]", v)
+		}
+	case json.Number:
+		params[key] = x.String()
+	case string:
+		params[key] = x
+	case bool:
+		params[key] = strconv.FormatBool(x)
+	case nil:
+		// null
+	}
+}
@@ -0,0 +1,203 @@
+package lob
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type formNested struct {
+	Name  string `json:"name"`
+	Count *int   `json:"count,omitempty"`
+}
+
+type formEmbedded struct {
+	Source string `json:"source"`
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestJSON2Form(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected map[string]string
+	}{
+		{
+			name: "strings",
+			value: struct {
+				A string  `json:"a"`
+				B *string `json:"b"`
+				C *string `json:"c"`
+			}{A: "x", B: nullString("y")},
+			expected: map[string]string{"a": "x", "b": "y"},
+		},
+		{
+			name: "explicit zero values",
+			value: struct {
+				Int   int     `json:"int"`
+				Bool  bool    `json:"bool"`
+				Float float64 `json:"float"`
+				Str   string  `json:"str"`
+			}{},
+			expected: map[string]string{"int": "0", "bool": "false", "float": "0", "str": ""},
+		},
+		{
+			name: "omitempty",
+			value: struct {
+				Int   int               `json:"int,omitempty"`
+				Bool  bool              `json:"bool,omitempty"`
+				Str   string            `json:"str,omitempty"`
+				Map   map[string]string `json:"map,omitempty"`
+				Slice []string          `json:"slice,omitempty"`
+				Kept  string            `json:"kept,omitempty"`
+			}{Kept: "yes"},
+			expected: map[string]string{"kept": "yes"},
+		},
+		{
+			name: "pointers to zero values",
+			value: struct {
+				Int   *int     `json:"int,omitempty"`
+				Float *float64 `json:"float,omitempty"`
+				Bool  *bool    `json:"bool,omitempty"`
+			}{Int: intPtr(0), Float: float64Ptr(0), Bool: boolPtr(false)},
+			expected: map[string]string{"int": "0", "float": "0", "bool": "false"},
+		},
+		{
+			name: "skipped fields",
+			value: struct {
+				Skipped    string `json:"-"`
+				unexported string
+				Dash       string `json:"-,"`
+			}{Skipped: "no", unexported: "no", Dash: "yes"},
+			expected: map[string]string{"-": "yes"},
+		},
+		{
+			name: "untagged and embedded fields",
+			value: struct {
+				formEmbedded
+				Plain string
+			}{formEmbedded{Source: "api"}, "text"},
+			expected: map[string]string{"source": "api", "Plain": "text"},
+		},
+		{
+			name: "floats are not rounded",
+			value: struct {
+				A float64 `json:"a"`
+				B float64 `json:"b"`
+				C float64 `json:"c"`
+			}{A: 987.65, B: 1234.005, C: 100},
+			expected: map[string]string{"a": "987.65", "b": "1234.005", "c": "100"},
+		},
+		{
+			name: "integers",
+			value: struct {
+				A int64  `json:"a"`
+				B uint8  `json:"b"`
+				C *int64 `json:"c"`
+			}{A: 9007199254740993, B: 7},
+			expected: map[string]string{"a": "9007199254740993", "b": "7"},
+		},
+		{
+			name: "time",
+			value: struct {
+				At    time.Time  `json:"at"`
+				Maybe *time.Time `json:"maybe"`
+			}{At: time.Date(2019, 6, 1, 12, 30, 0, 0, time.UTC)},
+			expected: map[string]string{"at": "2019-06-01T12:30:00Z"},
+		},
+		{
+			name: "nested structs",
+			value: struct {
+				Nested  formNested  `json:"nested"`
+				Pointer *formNested `json:"pointer"`
+				Nil     *formNested `json:"nil"`
+			}{Nested: formNested{Name: "a"}, Pointer: &formNested{Name: "b", Count: intPtr(2)}},
+			expected: map[string]string{"nested[name]": "a", "pointer[name]": "b", "pointer[count]": "2"},
+		},
+		{
+			name: "slices",
+			value: struct {
+				Strings []string     `json:"strings"`
+				Structs []formNested `json:"structs"`
+			}{Strings: []string{"a", "b"}, Structs: []formNested{{Name: "x"}, {Name: "y", Count: intPtr(0)}}},
+			expected: map[string]string{
+				"strings[0]":        "a",
+				"strings[1]":        "b",
+				"structs[0][name]":  "x",
+				"structs[1][name]":  "y",
+				"structs[1][count]": "0",
+			},
+		},
+		{
+			name: "maps",
+			value: struct {
+				Metadata map[string]string `json:"metadata"`
+			}{Metadata: map[string]string{"customer": "42"}},
+			expected: map[string]string{"metadata[customer]": "42"},
+		},
+		{
+			name: "address references",
+			value: CreateCheckRequest{
+				Amount: MustParseMoney("10"),
+				From:   AddressID("adr_123"),
+				To: InlineAddress(&Address{
+					Name:         nullString("Lobster Test"),
+					AddressLine1: "1005 W Burnside St",
+					Metadata:     map[string]string{"customer": "42"},
+				}),
+			},
+			expected: map[string]string{
+				"amount":                 "10.00",
+				"from":                   "adr_123",
+				"to[name]":               "Lobster Test",
+				"to[address_line1]":      "1005 W Burnside St",
+				"to[metadata][customer]": "42",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		params, err := json2form(test.value)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(params, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, params)
+		}
+	}
+}
+
+func TestJSON2FormErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"channel field", struct {
+			C chan int `json:"c"`
+		}{}},
+		{"function field", struct {
+			F func() `json:"f"`
+		}{F: func() {}}},
+		{"not an object", "string"},
+		{"slice", []string{"a"}},
+		{"nil", nil},
+	}
+
+	for _, test := range tests {
+		if _, err := json2form(test.value); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
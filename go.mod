@@ -0,0 +1,7 @@
+module github.com/seedco/go-lob
+
+go 1.24.0
+
+require github.com/pborman/uuid v1.2.1
+
+require github.com/google/uuid v1.6.0 // indirect
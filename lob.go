@@ -2,33 +2,17 @@ package lob
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"reflect"
-	"runtime"
-	"strconv"
 	"strings"
-
-	"github.com/op/go-logging"
+	"time"
 )
 
-var log = logging.MustGetLogger("lob")
-
-// LogStackTrace logs a stack trace for the given error.
-func logStackTrace(err error) {
-	buf := make([]byte, 0, 16384)
-	n := runtime.Stack(buf, false)
-	if err != nil {
-		log.Errorf("Non-nil error %s; stack trace %s", err.Error(), buf[:n])
-	} else {
-		log.Errorf("Nil error; stack trace %s", buf[:n])
-	}
-}
-
 type Lob interface {
 	// Checks
 	CreateCheck(*CreateCheckRequest) (*Check, error)
@@ -52,26 +36,80 @@ type Lob interface {
 
 // Lob represents information on how to connect to the lob.com API.
 type lob struct {
-	BaseAPI   string
+	BaseAPI string
+	// APIKey authenticates requests when neither Credentials nor Accounts are used.
 	APIKey    string
 	UserAgent string
+	// APIVersion is the Lob-Version sent with every request. It defaults to the package's
+	// APIVersion.
+	APIVersion string
+	// Environment selects which of an account's keys are used. It defaults to the environment
+	// APIKey belongs to.
+	Environment Environment
+	// AllowLive must be set for the client to send requests with a live key, so that a test
+	// configuration pointed at a live key fails instead of mailing real checks.
+	AllowLive bool
+	// Accounts are the Lob accounts the client can act for, by name. WithAccount selects one;
+	// otherwise requests use APIKey.
+	Accounts map[string]Account
+	// Credentials, if set, supplies keys in place of APIKey and Accounts.
+	Credentials Credentials
+	// Logger, if set, receives an entry for every request. The client logs nothing otherwise.
+	Logger Logger
+	// Metrics, if set, receives a measurement of every request.
+	Metrics Metrics
+	// Tracer, if set, starts a span for every request.
+	Tracer Tracer
+
+	account  string
+	attempt  int
+	ctx      context.Context
+	response *Response
 }
 
-// Base URL and API version for Lob.
+// Base URL and default API version for Lob.
 const (
 	BaseAPI    = "https://api.lob.com/v1/"
-	APIVersion = "2019-06-01"
+	APIVersion = APIVersion20190601
 )
 
 // NewLob creates an object that can be used to connect to the lob.com API.
 func NewLob(baseAPI, apiKey, userAgent string) *lob {
 	return &lob{
-		BaseAPI:   baseAPI,
-		APIKey:    apiKey,
-		UserAgent: userAgent,
+		BaseAPI:    baseAPI,
+		APIKey:     apiKey,
+		UserAgent:  userAgent,
+		APIVersion: APIVersion,
 	}
 }
 
+// String describes the client without revealing its API keys.
+func (l *lob) String() string {
+	return fmt.Sprintf("lob client for %s (key %s, version %s)", l.BaseAPI, redactKey(l.APIKey), l.version())
+}
+
+// GoString is like String, so that %#v does not reveal API keys either.
+func (l *lob) GoString() string {
+	return l.String()
+}
+
+// WithAPIVersion returns a copy of the client that sends requests with the given Lob-Version,
+// leaving the original client's version unchanged. It lets endpoints move to a new API version
+// one call site at a time.
+func (l *lob) WithAPIVersion(version string) *lob {
+	c := *l
+	c.APIVersion = version
+	return &c
+}
+
+// version returns the API version requests are sent with.
+func (l *lob) version() string {
+	if l.APIVersion == "" {
+		return APIVersion
+	}
+	return l.APIVersion
+}
+
 func queryParams(params map[string]string) string {
 	if params == nil {
 		return ""
@@ -83,185 +121,133 @@ func queryParams(params map[string]string) string {
 	return "?" + strings.Join(pieces, "&")
 }
 
-// Use JSON tag information to create a form values map.
-func json2form(v interface{}) map[string]string {
-	value := reflect.ValueOf(v)
-	t := value.Type()
-	params := make(map[string]string)
-	for i := 0; i < value.NumField(); i++ {
-		f := t.Field(i)
-		name := f.Tag.Get("json")
-		fv := value.Field(i).Interface()
-		if fv == nil {
-			continue
-		}
-		switch x := fv.(type) {
-		case *string:
-			if x != nil {
-				params[name] = *x
-			}
-		case string:
-			if x != "" {
-				params[name] = x
-			}
-		case int:
-			if x != 0 {
-				params[name] = strconv.Itoa(x)
-			}
-		case *bool:
-			if x != nil {
-				params[name] = fmt.Sprintf("%v", *x)
-			}
-		case int64:
-			if x != 0 {
-				params[name] = strconv.FormatInt(x, 10)
-			}
-		case float64:
-			params[name] = fmt.Sprintf("%.2f", x)
-		case []string:
-			if len(x) > 0 {
-				params[name] = strings.Join(x, " ")
-			}
-		case map[string]string:
-			for mapkey, mapvalue := range x {
-				params[name+"["+mapkey+"]"] = mapvalue
-			}
-		case *Error:
-			// do not turn into form values. This is for the return response only
-		default:
-			// ignore
-			panic(fmt.Errorf("Unknown field type: " + value.Field(i).Type().String()))
-		}
-	}
-	return params
+// requestBody is an encoded request body along with its content type.
+type requestBody struct {
+	contentType string
+	reader      io.Reader
 }
 
-// Get performs a GET request to the lob API.
-func (l *lob) get(endpoint string, params map[string]string, returnValue interface{}) error {
-	fullURL := l.BaseAPI + endpoint + queryParams(params)
-	log.Debugf("Lob GET %s", fullURL)
-	req, err := http.NewRequest("GET", fullURL, nil)
+// jsonBody encodes v as a JSON request body.
+func jsonBody(v interface{}) (*requestBody, error) {
+	data, err := json.Marshal(v)
 	if err != nil {
-		logStackTrace(err)
-		return err
+		return nil, err
 	}
+	return &requestBody{
+		contentType: "application/json",
+		reader:      bytes.NewReader(data),
+	}, nil
+}
 
-	req.SetBasicAuth(l.APIKey, "")
-	req.Header.Add("Lob-Version", APIVersion)
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("User-Agent", l.UserAgent)
+// Get performs a GET request to the lob API.
+func (l *lob) get(endpoint string, params map[string]string, returnValue interface{}) error {
+	return l.do("GET", endpoint+queryParams(params), nil, returnValue)
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		logStackTrace(err)
-		return err
+// Post performs a POST request to the Lob API, sending v as a JSON body, or as a multipart body
+// if it has files to upload.
+func (l *lob) post(endpoint string, v interface{}, returnValue interface{}) error {
+	if r, ok := v.(versionedRequest); ok {
+		v = r.forVersion(l.version())
 	}
-	defer resp.Body.Close()
+	return l.do("POST", endpoint, v, returnValue)
+}
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		logStackTrace(err)
-		return err
-	}
+// Delete performs a DELETE request to the Lob API.
+func (l *lob) delete(endpoint string, returnValue interface{}) error {
+	return l.do("DELETE", endpoint, nil, returnValue)
+}
 
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("Non-200 status code %d returned from %s with body %s", resp.StatusCode, fullURL, data)
-		logStackTrace(err)
-		json.Unmarshal(data, returnValue) // try, anyway -- in case the caller wants error info
-		return err
+// encodeBody encodes v as a JSON request body, or as a multipart body if it has files to upload.
+func encodeBody(v interface{}) (*requestBody, error) {
+	if len(fileUploads(v)) > 0 {
+		return multipartBody(v)
 	}
-
-	return json.Unmarshal(data, returnValue)
+	return jsonBody(v)
 }
 
-// Post performs a POST request to the Lob API.
-func (l *lob) post(endpoint string, params map[string]string, returnValue interface{}) error {
+// do performs a request to the Lob API, sending v as its body unless it is nil, and decodes the
+// JSON response into returnValue. The request is logged, measured and traced even if its body
+// cannot be encoded.
+func (l *lob) do(method, endpoint string, v interface{}, returnValue interface{}) (err error) {
 	fullURL := l.BaseAPI + endpoint
-	log.Debugf("Lob POST %s", fullURL)
-
-	var body io.Reader
-	if params != nil {
-		form := url.Values(make(map[string][]string))
-		for k, v := range params {
-			form.Add(k, v)
+	start := time.Now()
+	ctx, span := l.startSpan(l.context(), method, endpoint)
+	var status int
+	var requestID string
+	var data []byte
+	defer func() {
+		latency := time.Since(start)
+		l.logRequest(method, endpoint, status, requestID, latency, err)
+		l.recordMetrics(method, endpoint, status, latency, err)
+		endSpan(span, endpoint, status, requestID, data, err)
+	}()
+
+	if l.response != nil {
+		*l.response = Response{}
+	}
+
+	var body *requestBody
+	if v != nil {
+		if body, err = encodeBody(v); err != nil {
+			return err
 		}
-		bodyString := form.Encode()
-		body = bytes.NewBuffer([]byte(bodyString))
 	}
-
-	req, err := http.NewRequest("POST", fullURL, body)
+	var reader io.Reader
+	if body != nil {
+		reader = body.reader
+	}
+	apiKey, err := l.apiKey()
+	var req *http.Request
+	if err == nil {
+		req, err = http.NewRequestWithContext(ctx, method, fullURL, reader)
+	}
 	if err != nil {
-		logStackTrace(err)
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close() // stop any goroutine streaming the body
+		}
 		return err
 	}
 
 	if body != nil {
-		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Content-Type", body.contentType)
 	}
 
-	req.SetBasicAuth(l.APIKey, "")
-	req.Header.Add("Lob-Version", APIVersion)
+	req.SetBasicAuth(apiKey, "")
+	req.Header.Add("Lob-Version", l.version())
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("User-Agent", l.UserAgent)
+	if l.Tracer != nil {
+		l.Tracer.Inject(ctx, req.Header)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		logStackTrace(err)
 		return err
 	}
 	defer resp.Body.Close()
+	status = resp.StatusCode
+	requestID = resp.Header.Get(requestIDHeader)
 
-	data, err := ioutil.ReadAll(resp.Body)
+	data, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logStackTrace(err)
 		return err
 	}
-
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("Non-200 status code %d returned from %s with body %s", resp.StatusCode, fullURL, data)
-		logStackTrace(err)
-		json.Unmarshal(data, returnValue) // try, anyway -- in case the caller wants error info
-		return err
+	if l.response != nil {
+		*l.response = newResponse(resp, data)
 	}
 
-	return json.Unmarshal(data, returnValue)
-}
-
-// Delete performs a DELETE request to the Lob API.
-func (l *lob) delete(endpoint string, returnValue interface{}) error {
-	fullURL := l.BaseAPI + endpoint
-	log.Debugf("Lob DELETE %s", fullURL)
-
-	req, err := http.NewRequest("DELETE", fullURL, nil)
-	if err != nil {
-		logStackTrace(err)
-		return err
-	}
-
-	req.SetBasicAuth(l.APIKey, "")
-	req.Header.Add("Lob-Version", APIVersion)
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("User-Agent", l.UserAgent)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		logStackTrace(err)
-		return err
+	if resp.StatusCode != 200 {
+		body := redactBody(data)
+		json.Unmarshal(body, returnValue) // try, anyway -- in case the caller wants error info
+		return &APIError{StatusCode: resp.StatusCode, URL: redactURL(fullURL), Body: body}
 	}
-	defer resp.Body.Close()
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		logStackTrace(err)
+	if err := json.Unmarshal(data, returnValue); err != nil {
 		return err
 	}
-
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("Non-200 status code %d returned from %s with body %s", resp.StatusCode, fullURL, data)
-		logStackTrace(err)
-		json.Unmarshal(data, returnValue) // try, anyway -- in case the caller wants error info
-		return err
+	if r, ok := returnValue.(versionedResponse); ok {
+		r.fromVersion(l.version())
 	}
-
-	return json.Unmarshal(data, returnValue)
+	return nil
 }
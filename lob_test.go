@@ -142,9 +142,9 @@ func TestChecks(t *testing.T) {
 	check, err := lob.CreateCheck(&CreateCheckRequest{
 		CheckNumber:   nullString("12345"),
 		BankAccountID: bankAccount.ID,
-		FromAddressID: address.ID,
-		ToAddressID:   address.ID,
-		Amount:        987.65,
+		From:          AddressID(address.ID),
+		To:            AddressID(address.ID),
+		Amount:        MustParseMoney("987.65"),
 		Message:       nullString("Some message"),
 		Memo:          nullString("A memo"),
 	})
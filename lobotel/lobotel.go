@@ -0,0 +1,85 @@
+// Package lobotel traces the requests of a Lob client with OpenTelemetry.
+//
+//	client.Tracer = lobotel.NewTracer(otel.GetTracerProvider(), nil)
+//	check, err := client.WithContext(ctx).GetCheck(id)
+package lobotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	lob "github.com/seedco/go-lob"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans this package creates.
+const instrumentationName = "github.com/seedco/go-lob"
+
+// NewTracer returns a lob.Tracer that starts client spans from provider and injects trace
+// context into requests with propagator, or with the global propagator if it is nil.
+func NewTracer(provider trace.TracerProvider, propagator propagation.TextMapPropagator) lob.Tracer {
+	return &tracer{
+		tracer:     provider.Tracer(instrumentationName),
+		propagator: propagator,
+	}
+}
+
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func (t *tracer) Start(ctx context.Context, name string) (context.Context, lob.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, otelSpan{span}
+}
+
+func (t *tracer) Inject(ctx context.Context, header http.Header) {
+	propagator := t.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetAttributes(fields ...lob.Field) {
+	attributes := make([]attribute.KeyValue, len(fields))
+	for i, f := range fields {
+		attributes[i] = toAttribute(f)
+	}
+	s.span.SetAttributes(attributes...)
+}
+
+func (s otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// toAttribute converts a field to an attribute of the matching type.
+func toAttribute(f lob.Field) attribute.KeyValue {
+	switch v := f.Value.(type) {
+	case string:
+		return attribute.String(f.Key, v)
+	case int:
+		return attribute.Int(f.Key, v)
+	case int64:
+		return attribute.Int64(f.Key, v)
+	case bool:
+		return attribute.Bool(f.Key, v)
+	case float64:
+		return attribute.Float64(f.Key, v)
+	}
+	return attribute.String(f.Key, fmt.Sprint(f.Value))
+}
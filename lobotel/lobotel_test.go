@@ -0,0 +1,78 @@
+package lobotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	lob "github.com/seedco/go-lob"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.Header().Set("X-Request-Id", "req_123")
+		if r.URL.Path == "/checks/chk_missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{"id": "chk_123"}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := lob.NewLob(server.URL+"/", "test_key", "go-lob tests")
+	client.Tracer = NewTracer(provider, propagation.TraceContext{})
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "payout")
+	if _, err := client.WithContext(ctx).GetCheck("chk_123"); err != nil {
+		t.Fatal(err)
+	}
+	client.WithContext(ctx).GetCheck("chk_missing")
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	found, missing := spans[0], spans[1]
+
+	if found.Name() != "lob GET checks/:id" || found.SpanKind() != trace.SpanKindClient {
+		t.Errorf("Expected a client span for the route, got %s (%s)", found.Name(), found.SpanKind())
+	}
+	if found.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("Expected the span to be a child of the caller's span")
+	}
+	if traceparent == "" {
+		t.Error("Expected the trace context to be sent with the request")
+	}
+
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range found.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	expected := map[attribute.Key]attribute.Value{
+		lob.AttributeHTTPMethod:     attribute.StringValue("GET"),
+		lob.AttributeHTTPRoute:      attribute.StringValue("checks/:id"),
+		lob.AttributeHTTPStatusCode: attribute.IntValue(200),
+		lob.AttributeLobRequestID:   attribute.StringValue("req_123"),
+		"check.id":                  attribute.StringValue("chk_123"),
+	}
+	for k, v := range expected {
+		if attributes[k] != v {
+			t.Errorf("Expected %s = %v, got %v", k, v.Emit(), attributes[k].Emit())
+		}
+	}
+
+	if missing.Status().Code != codes.Error || len(missing.Events()) == 0 {
+		t.Errorf("Expected the failed request's span to record its error, got %+v", missing.Status())
+	}
+}
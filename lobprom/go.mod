@@ -0,0 +1,23 @@
+module github.com/seedco/go-lob/lobprom
+
+go 1.24.0
+
+require (
+	github.com/prometheus/client_golang v1.19.1
+	github.com/seedco/go-lob v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/prometheus/client_model v0.5.0 // indirect
+	github.com/prometheus/common v0.48.0 // indirect
+	github.com/prometheus/procfs v0.12.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+)
+
+// Builds and tests use the go-lob in this repository. Releases tag go-lob first and require
+// that version.
+replace github.com/seedco/go-lob => ../
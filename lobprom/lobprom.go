@@ -0,0 +1,85 @@
+// Package lobprom exports the metrics of a Lob client to Prometheus.
+//
+//	collector := lobprom.NewCollector("myapp")
+//	prometheus.MustRegister(collector)
+//	client.Metrics = collector
+package lobprom
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	lob "github.com/seedco/go-lob"
+)
+
+// Collector is a lob.Metrics that keeps Prometheus metrics of Lob requests, labelled by method
+// and normalized endpoint:
+//
+//   - lob_requests_total, a counter that is also labelled by status code, with "0" for requests
+//     that got no response;
+//   - lob_request_errors_total, a counter of requests that returned an error, labelled by status;
+//   - lob_request_duration_seconds, a histogram of request latency;
+//   - lob_request_retries_total, a counter of attempts after the first, as set by the client's
+//     WithAttempt.
+type Collector struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	retries  *prometheus.CounterVec
+}
+
+// NewCollector returns a Collector whose metrics are prefixed by namespace, if it is not empty.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "lob_requests_total",
+			Help:      "Requests made to the Lob API.",
+		}, []string{"method", "endpoint", "status"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "lob_request_errors_total",
+			Help:      "Requests to the Lob API that returned an error.",
+		}, []string{"method", "endpoint", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "lob_request_duration_seconds",
+			Help:      "Latency of requests to the Lob API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "lob_request_retries_total",
+			Help:      "Retried attempts at requests to the Lob API.",
+		}, []string{"method", "endpoint"}),
+	}
+}
+
+// ObserveRequest implements lob.Metrics.
+func (c *Collector) ObserveRequest(r lob.RequestMetrics) {
+	status := strconv.Itoa(r.Status)
+	c.requests.WithLabelValues(r.Method, r.Endpoint, status).Inc()
+	if r.Failed {
+		c.errors.WithLabelValues(r.Method, r.Endpoint, status).Inc()
+	}
+	c.duration.WithLabelValues(r.Method, r.Endpoint).Observe(r.Latency.Seconds())
+	if r.Attempt > 1 {
+		c.retries.WithLabelValues(r.Method, r.Endpoint).Inc()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.duration.Describe(ch)
+	c.retries.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.duration.Collect(ch)
+	c.retries.Collect(ch)
+}
@@ -0,0 +1,38 @@
+package lobprom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	lob "github.com/seedco/go-lob"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("test")
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(c); err != nil {
+		t.Fatal(err)
+	}
+
+	c.ObserveRequest(lob.RequestMetrics{Method: "GET", Endpoint: "checks/:id", Status: 200, Latency: 20 * time.Millisecond, Attempt: 1})
+	c.ObserveRequest(lob.RequestMetrics{Method: "GET", Endpoint: "checks/:id", Status: 404, Latency: 10 * time.Millisecond, Attempt: 1, Failed: true})
+	c.ObserveRequest(lob.RequestMetrics{Method: "POST", Endpoint: "checks", Status: 200, Latency: time.Second, Attempt: 2})
+
+	if n := testutil.ToFloat64(c.requests.WithLabelValues("GET", "checks/:id", "200")); n != 1 {
+		t.Errorf("Expected 1 successful GET, got %v", n)
+	}
+	if n := testutil.ToFloat64(c.errors.WithLabelValues("GET", "checks/:id", "404")); n != 1 {
+		t.Errorf("Expected 1 failed GET, got %v", n)
+	}
+	if n := testutil.ToFloat64(c.retries.WithLabelValues("POST", "checks")); n != 1 {
+		t.Errorf("Expected 1 retry, got %v", n)
+	}
+	if n := testutil.CollectAndCount(c, "test_lob_request_duration_seconds"); n != 2 {
+		t.Errorf("Expected latency histograms for 2 endpoints, got %d", n)
+	}
+	if _, err := registry.Gather(); err != nil {
+		t.Errorf("Expected the metrics to be consistent, got %v", err)
+	}
+}
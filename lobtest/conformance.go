@@ -0,0 +1,509 @@
+package lobtest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	lob "github.com/seedco/go-lob"
+)
+
+// missingID is an ID no Lob object has.
+const missingID = "0000000000000000"
+
+// conformanceMetadataKey is the metadata key of the bank account the suite reuses.
+const conformanceMetadataKey = "lobtest_conformance"
+
+// maxListPages is how many pages of a list the suite looks through for its objects, which other
+// objects created in a live account at the same time may be listed before.
+const maxListPages = 10
+
+// Clock is implemented by Lobs whose time can be moved forward, such as lob.FakeLob. The suite
+// uses it to check that checks past their send date can't be cancelled, and skips that case for
+// Lobs that don't implement it.
+type Clock interface {
+	// Advance moves the Lob's time forward by d.
+	Advance(d time.Duration)
+}
+
+// RunConformance checks that a Lob implementation behaves the way the Lob interface documents,
+// so that fakes can be trusted to stand in for the client. newLob is called once per subtest:
+//
+//	lobtest.RunConformance(t, func() lob.Lob { return lob.NewFakeLob() })
+//
+// The suite only looks at objects of its own, and pages through lists for them when the
+// implementation is a lob.Pager, so it can run against an account that has others, such as Lob's
+// test environment. It deletes the addresses it creates and cancels its checks, which Lob keeps
+// marked as deleted. Bank accounts can't be deleted through the Lob interface, so it creates one
+// bank account, tagged in its metadata, and reuses it on later runs.
+func RunConformance(t *testing.T, newLob func() lob.Lob) {
+	t.Run("Addresses", func(t *testing.T) { testAddresses(t, newLob()) })
+	t.Run("BankAccounts", func(t *testing.T) { testBankAccounts(t, newLob()) })
+	t.Run("Checks", func(t *testing.T) { testChecks(t, newLob()) })
+	t.Run("CancelSentCheck", func(t *testing.T) { testCancelSentCheck(t, newLob()) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, newLob()) })
+	t.Run("Pagination", func(t *testing.T) { testPagination(t, newLob()) })
+	t.Run("Validation", func(t *testing.T) { testValidation(t, newLob()) })
+	t.Run("Verification", func(t *testing.T) { testVerification(t, newLob()) })
+	t.Run("StatesAndCountries", func(t *testing.T) { testStatesAndCountries(t, newLob()) })
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// testAddress returns the address the suite and the package's tests create.
+func testAddress() *lob.Address {
+	return &lob.Address{
+		Name:           stringPtr("Lobster Test"),
+		AddressLine1:   "1005 W Burnside St",
+		AddressCity:    stringPtr("Portland"),
+		AddressState:   stringPtr("OR"),
+		AddressZip:     stringPtr("97209"),
+		AddressCountry: stringPtr("US"),
+	}
+}
+
+// testBankAccount returns a request for the bank account the suite and the package's tests
+// create.
+func testBankAccount() *lob.CreateBankAccountRequest {
+	return &lob.CreateBankAccountRequest{
+		RoutingNumber: "322271627",
+		AccountNumber: "123456789",
+		Signatory:     "John Doe",
+		AccountType:   lob.AccountTypeCompany,
+	}
+}
+
+// conformanceBankAccount returns the bank account the suite reuses, creating it if it is not
+// listed yet.
+func conformanceBankAccount(t *testing.T, l lob.Lob) *lob.BankAccount {
+	t.Helper()
+	id, ok := findListed(t, bankAccountPages(l), func(o listedObject) bool {
+		return o.metadata[conformanceMetadataKey] == "true"
+	})
+	if ok {
+		bankAccount, err := l.GetBankAccount(id)
+		if err != nil {
+			t.Fatalf("Could not get bank account %s: %s", id, err)
+		}
+		return bankAccount
+	}
+	request := testBankAccount()
+	request.Metadata = map[string]string{conformanceMetadataKey: "true"}
+	bankAccount, err := l.CreateBankAccount(request)
+	if err != nil {
+		t.Fatalf("Could not create bank account: %s", err)
+	}
+	return bankAccount
+}
+
+// listedObject is an object in a page of a list.
+type listedObject struct {
+	id       string
+	metadata map[string]string
+}
+
+// listPage is a page of a list and the URL of the page after it, if it can be followed.
+type listPage struct {
+	objects []listedObject
+	nextURL string
+}
+
+// eachListed calls visit with the objects in up to maxListPages pages of a list, newest first,
+// until it returns false. pages returns the first page for an empty page URL.
+func eachListed(t *testing.T, pages func(pageURL string) (listPage, error), visit func(listedObject) bool) {
+	t.Helper()
+	pageURL := ""
+	for i := 0; i < maxListPages; i++ {
+		page, err := pages(pageURL)
+		if err != nil {
+			t.Fatalf("Could not list: %s", err)
+		}
+		for _, o := range page.objects {
+			if !visit(o) {
+				return
+			}
+		}
+		if page.nextURL == "" {
+			return
+		}
+		pageURL = page.nextURL
+	}
+}
+
+// findListed returns the ID of the newest listed object match returns true for.
+func findListed(t *testing.T, pages func(pageURL string) (listPage, error), match func(listedObject) bool) (string, bool) {
+	t.Helper()
+	var id string
+	eachListed(t, pages, func(o listedObject) bool {
+		if match(o) {
+			id = o.id
+		}
+		return id == ""
+	})
+	return id, id != ""
+}
+
+// isListed reports whether the object with the ID is listed.
+func isListed(t *testing.T, pages func(pageURL string) (listPage, error), id string) bool {
+	t.Helper()
+	_, ok := findListed(t, pages, func(o listedObject) bool { return o.id == id })
+	return ok
+}
+
+func addressPages(l lob.Lob) func(string) (listPage, error) {
+	pager, _ := l.(lob.Pager)
+	return func(pageURL string) (listPage, error) {
+		var list *lob.ListAddressesResponse
+		var err error
+		if pageURL == "" {
+			list, err = l.ListAddresses(100)
+		} else {
+			list, err = pager.ListAddressesPage(pageURL)
+		}
+		if err != nil {
+			return listPage{}, err
+		}
+		var page listPage
+		for _, address := range list.Data {
+			page.objects = append(page.objects, listedObject{id: address.ID, metadata: address.Metadata})
+		}
+		if pager != nil {
+			page.nextURL = list.NextURL
+		}
+		return page, nil
+	}
+}
+
+func bankAccountPages(l lob.Lob) func(string) (listPage, error) {
+	pager, _ := l.(lob.Pager)
+	return func(pageURL string) (listPage, error) {
+		var list *lob.ListBankAccountsResponse
+		var err error
+		if pageURL == "" {
+			list, err = l.ListBankAccounts(100)
+		} else {
+			list, err = pager.ListBankAccountsPage(pageURL)
+		}
+		if err != nil {
+			return listPage{}, err
+		}
+		var page listPage
+		for _, bankAccount := range list.Data {
+			page.objects = append(page.objects, listedObject{id: bankAccount.ID, metadata: bankAccount.Metadata})
+		}
+		if pager != nil {
+			page.nextURL = list.NextURL
+		}
+		return page, nil
+	}
+}
+
+func checkPages(l lob.Lob) func(string) (listPage, error) {
+	pager, _ := l.(lob.Pager)
+	return func(pageURL string) (listPage, error) {
+		var list *lob.ListChecksResponse
+		var err error
+		if pageURL == "" {
+			list, err = l.ListChecks(100)
+		} else {
+			list, err = pager.ListChecksPage(pageURL)
+		}
+		if err != nil {
+			return listPage{}, err
+		}
+		var page listPage
+		for _, check := range list.Data {
+			page.objects = append(page.objects, listedObject{id: check.ID, metadata: check.Metadata})
+		}
+		if pager != nil {
+			page.nextURL = list.NextURL
+		}
+		return page, nil
+	}
+}
+
+func createAddress(t *testing.T, l lob.Lob) *lob.Address {
+	t.Helper()
+	address, err := l.CreateAddress(testAddress())
+	if err != nil {
+		t.Fatalf("Could not create address: %s", err)
+	}
+	if address.ID == "" {
+		t.Fatal("Expected the created address to have an ID")
+	}
+	return address
+}
+
+func deleteAddress(t *testing.T, l lob.Lob, id string) {
+	t.Helper()
+	if err := l.DeleteAddress(id); err != nil {
+		t.Errorf("Could not delete address %s: %s", id, err)
+	}
+}
+
+func testAddresses(t *testing.T, l lob.Lob) {
+	address := createAddress(t, l)
+	if address.AddressLine1 == "" || address.DateCreated.IsZero() {
+		t.Errorf("Expected the created address to be returned, got %+v", address)
+	}
+
+	got, err := l.GetAddress(address.ID)
+	if err != nil || got.ID != address.ID || got.Error != nil {
+		t.Errorf("Expected to get address %s, got %+v, %v", address.ID, got, err)
+	}
+
+	if !isListed(t, addressPages(l), address.ID) {
+		t.Errorf("Expected address %s to be listed", address.ID)
+	}
+
+	deleteAddress(t, l, address.ID)
+	got, err = l.GetAddress(address.ID)
+	if err != nil || got.ID != address.ID || got.Deleted == nil || !*got.Deleted {
+		t.Errorf("Expected deleted address %s to be marked deleted, got %+v, %v", address.ID, got, err)
+	}
+	if isListed(t, addressPages(l), address.ID) {
+		t.Errorf("Expected deleted address %s not to be listed", address.ID)
+	}
+}
+
+func testBankAccounts(t *testing.T, l lob.Lob) {
+	bankAccount := conformanceBankAccount(t, l)
+	if bankAccount.ID == "" || bankAccount.Object != "bank_account" || bankAccount.Metadata[conformanceMetadataKey] != "true" {
+		t.Errorf("Expected the bank account with its metadata, got %+v", bankAccount)
+	}
+
+	got, err := l.GetBankAccount(bankAccount.ID)
+	if err != nil || got.ID != bankAccount.ID || got.RoutingNumber != bankAccount.RoutingNumber {
+		t.Errorf("Expected to get bank account %s, got %+v, %v", bankAccount.ID, got, err)
+	}
+
+	if !isListed(t, bankAccountPages(l), bankAccount.ID) {
+		t.Errorf("Expected bank account %s to be listed", bankAccount.ID)
+	}
+}
+
+func testChecks(t *testing.T, l lob.Lob) {
+	from := createAddress(t, l)
+	defer deleteAddress(t, l, from.ID)
+	bankAccount := conformanceBankAccount(t, l)
+
+	amount := lob.MustParseMoney("987.65")
+	check, err := l.CreateCheck(&lob.CreateCheckRequest{
+		Amount:        amount,
+		BankAccountID: bankAccount.ID,
+		From:          lob.AddressID(from.ID),
+		To:            lob.InlineAddress(testAddress()),
+		Memo:          stringPtr("conformance"),
+	})
+	if err != nil {
+		t.Fatalf("Could not create check: %s", err)
+	}
+	if check.ID == "" || check.Amount != amount || check.Memo != "conformance" || check.Object != "check" {
+		t.Errorf("Expected the created check to be returned, got %+v", check)
+	}
+	if check.To == nil || check.To.ID == "" || check.From == nil || check.From.ID != from.ID {
+		t.Errorf("Expected the check's addresses, got to %+v and from %+v", check.To, check.From)
+	}
+	if check.To != nil && check.To.ID != "" {
+		defer deleteAddress(t, l, check.To.ID)
+	}
+	if !check.Cancellable(check.DateCreated) {
+		t.Errorf("Expected a new check to be cancellable until %s", check.SendDate)
+	}
+
+	got, err := l.GetCheck(check.ID)
+	if err != nil || got.ID != check.ID || got.Amount != amount {
+		t.Errorf("Expected to get check %s, got %+v, %v", check.ID, got, err)
+	}
+	if !isListed(t, checkPages(l), check.ID) {
+		t.Errorf("Expected check %s to be listed", check.ID)
+	}
+
+	cancelled, err := l.CancelCheck(check.ID)
+	if err != nil || cancelled.ID != check.ID || !cancelled.Deleted {
+		t.Errorf("Expected check %s to be cancelled, got %+v, %v", check.ID, cancelled, err)
+	}
+	if got, err := l.GetCheck(check.ID); err != nil || !got.Deleted {
+		t.Errorf("Expected cancelled check %s to be marked deleted, got %+v, %v", check.ID, got, err)
+	}
+	if isListed(t, checkPages(l), check.ID) {
+		t.Errorf("Expected cancelled check %s not to be listed", check.ID)
+	}
+}
+
+func testCancelSentCheck(t *testing.T, l lob.Lob) {
+	clock, ok := l.(Clock)
+	if !ok {
+		t.Skip("The Lob's time can't be moved past a check's send date")
+	}
+	address := createAddress(t, l)
+	defer deleteAddress(t, l, address.ID)
+	check, err := l.CreateCheck(&lob.CreateCheckRequest{
+		Amount:        lob.MustParseMoney("1"),
+		BankAccountID: conformanceBankAccount(t, l).ID,
+		From:          lob.AddressID(address.ID),
+		To:            lob.AddressID(address.ID),
+	})
+	if err != nil {
+		t.Fatalf("Could not create check: %s", err)
+	}
+
+	clock.Advance(check.SendDate.Sub(check.DateCreated) + time.Minute)
+	_, err = l.CancelCheck(check.ID)
+	var apiErr *lob.APIError
+	if !errors.Is(err, lob.ErrCheckNotCancellable) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected cancelling a sent check to be a 422 matching ErrCheckNotCancellable, got %v", err)
+	}
+	if got, err := l.GetCheck(check.ID); err != nil || got.Deleted {
+		t.Errorf("Expected the sent check %s not to be deleted, got %+v, %v", check.ID, got, err)
+	}
+}
+
+func testNotFound(t *testing.T, l lob.Lob) {
+	check, err := l.GetCheck("chk_" + missingID)
+	if !errors.Is(err, lob.ErrNotFound) || check == nil || check.Error == nil || check.Error.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a missing check to be a 404 with Lob's error, got %+v, %v", check, err)
+	}
+	address, err := l.GetAddress("adr_" + missingID)
+	if !errors.Is(err, lob.ErrNotFound) || address == nil || address.Error == nil || address.Error.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a missing address to be a 404 with Lob's error, got %+v, %v", address, err)
+	}
+	if err := l.DeleteAddress("adr_" + missingID); !errors.Is(err, lob.ErrNotFound) {
+		t.Errorf("Expected deleting a missing address to be a 404, got %v", err)
+	}
+	if _, err := l.CancelCheck("chk_" + missingID); !errors.Is(err, lob.ErrNotFound) {
+		t.Errorf("Expected cancelling a missing check to be a 404, got %v", err)
+	}
+	bankAccount, err := l.GetBankAccount("bank_" + missingID)
+	if !errors.Is(err, lob.ErrNotFound) || bankAccount == nil || bankAccount.Error == nil || bankAccount.Error.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a missing bank account to be a 404 with Lob's error, got %+v, %v", bankAccount, err)
+	}
+}
+
+func testPagination(t *testing.T, l lob.Lob) {
+	var ids []string
+	for i := 0; i < 3; i++ {
+		address := createAddress(t, l)
+		defer deleteAddress(t, l, address.ID)
+		ids = append(ids, address.ID)
+	}
+
+	first, err := l.ListAddresses(2)
+	if err != nil {
+		t.Fatalf("Could not list addresses: %s", err)
+	}
+	if len(first.Data) != 2 || first.Count != 2 {
+		t.Fatalf("Expected 2 addresses and a count of 2, got %d and %d", len(first.Data), first.Count)
+	}
+	if first.NextURL == "" || first.PreviousURL != "" {
+		t.Errorf("Expected the first page to link to the next page only, got %q and %q", first.NextURL, first.PreviousURL)
+	}
+
+	if list, err := l.ListAddresses(0); err != nil || list.Count == 0 || list.Count > 10 {
+		t.Errorf("Expected the default page of at most 10 addresses, got %+v, %v", list, err)
+	}
+	if _, err := l.ListAddresses(101); err == nil {
+		t.Error("Expected listing more than 100 addresses to fail")
+	}
+
+	// Other addresses created at the same time may come first, but the suite's are listed newest
+	// first.
+	var listed []string
+	eachListed(t, addressPages(l), func(o listedObject) bool {
+		for _, id := range ids {
+			if o.id == id {
+				listed = append(listed, id)
+			}
+		}
+		return len(listed) < len(ids)
+	})
+	if expected := []string{ids[2], ids[1], ids[0]}; strings.Join(listed, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected the addresses to be listed newest first as %v, got %v", expected, listed)
+	}
+
+	pager, ok := l.(lob.Pager)
+	if !ok {
+		return
+	}
+	second, err := pager.ListAddressesPage(first.NextURL)
+	if err != nil {
+		t.Fatalf("Could not list the next page: %s", err)
+	}
+	if len(second.Data) == 0 || second.PreviousURL == "" {
+		t.Fatalf("Expected the next page to link back to the first, got %+v", second)
+	}
+	previous, err := pager.ListAddressesPage(second.PreviousURL)
+	if err != nil || addressIDs(previous.Data) != addressIDs(first.Data) {
+		t.Errorf("Expected the previous page to be the first page %s, got %+v, %v", addressIDs(first.Data), previous, err)
+	}
+}
+
+// addressIDs joins the IDs of the addresses, to compare pages.
+func addressIDs(addresses []lob.Address) string {
+	ids := make([]string, len(addresses))
+	for i, address := range addresses {
+		ids[i] = address.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func testValidation(t *testing.T, l lob.Lob) {
+	address, err := l.CreateAddress(&lob.Address{AddressLine1: "1005 W Burnside St"})
+	var errs lob.ValidationErrors
+	if !errors.As(err, &errs) || address == nil || address.Error == nil || address.Error.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected an incomplete address to fail validation with a 422, got %+v, %v", address, err)
+	}
+
+	request := testBankAccount()
+	request.RoutingNumber = "00000000"
+	bankAccount, err := l.CreateBankAccount(request)
+	if !errors.As(err, &errs) || bankAccount == nil || bankAccount.Error == nil {
+		t.Errorf("Expected an invalid routing number to fail validation, got %+v, %v", bankAccount, err)
+	}
+
+	check, err := l.CreateCheck(&lob.CreateCheckRequest{
+		Amount:        lob.MustParseMoney("1000000"),
+		BankAccountID: "bank_" + missingID,
+		From:          lob.InlineAddress(testAddress()),
+		To:            lob.InlineAddress(testAddress()),
+	})
+	if !errors.Is(err, lob.ErrCheckAmountTooLarge) || check == nil || check.Error == nil {
+		t.Errorf("Expected a check over the maximum amount to fail validation, got %+v, %v", check, err)
+	}
+}
+
+func testVerification(t *testing.T, l lob.Lob) {
+	address := testAddress()
+	address.AddressLine2 = nil
+	resp, err := l.VerifyUSAddress(address)
+	if err != nil || resp.Id == "" || resp.Deliverability == "" {
+		t.Errorf("Expected the address to be verified, got %+v, %v", resp, err)
+	}
+}
+
+func testStatesAndCountries(t *testing.T, l lob.Lob) {
+	states, err := l.GetStates()
+	if err != nil || !containsNamedObject(states, "OR") {
+		t.Errorf("Expected Oregon among the states, got %+v, %v", states, err)
+	}
+	countries, err := l.GetCountries()
+	if err != nil || !containsNamedObject(countries, "US") {
+		t.Errorf("Expected the United States among the countries, got %+v, %v", countries, err)
+	}
+}
+
+func containsNamedObject(list *lob.NamedObjectList, shortName string) bool {
+	if list == nil {
+		return false
+	}
+	for _, o := range list.Data {
+		if o.ShortName == shortName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package lobtest
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	lob "github.com/seedco/go-lob"
+)
+
+func TestConformanceFake(t *testing.T) {
+	RunConformance(t, func() lob.Lob {
+		return lob.NewFakeLob()
+	})
+}
+
+func TestConformanceServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	RunConformance(t, func() lob.Lob {
+		return serverClient{lob.NewLob(server.BaseAPI(), TestAPIKey, testUserAgent), server}
+	})
+}
+
+// serverClient is a client of a Server that moves the server's time as its own, so that the
+// suite can check that sent checks can't be cancelled through the client.
+type serverClient struct {
+	pagingLob
+	server *Server
+}
+
+type pagingLob interface {
+	lob.Lob
+	lob.Pager
+}
+
+func (c serverClient) Advance(d time.Duration) {
+	c.server.Advance(d)
+}
+
+func TestConformanceLive(t *testing.T) {
+	key := os.Getenv("TEST_LOB_API_KEY")
+	if key == "" {
+		t.Skip("TEST_LOB_API_KEY is not set")
+	}
+	RunConformance(t, func() lob.Lob {
+		return lob.NewLob(lob.BaseAPI, key, testUserAgent)
+	})
+}
@@ -0,0 +1,204 @@
+package lobtest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	lob "github.com/seedco/go-lob"
+)
+
+// writeResult writes v, the result of a call to the fake, or the response Lob sends for the
+// error the call returned.
+func writeResult(w http.ResponseWriter, v interface{}, err error) {
+	var apiErr *lob.APIError
+	var errs lob.ValidationErrors
+	switch {
+	case errors.As(err, &apiErr):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(apiErr.StatusCode)
+		w.Write(apiErr.Body)
+	case errors.As(err, &errs):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, v)
+	}
+}
+
+// listResponse is Lob's list format.
+type listResponse struct {
+	Data        interface{} `json:"data"`
+	Object      string      `json:"object"`
+	NextURL     *string     `json:"next_url"`
+	PreviousURL *string     `json:"previous_url"`
+	Count       int         `json:"count"`
+}
+
+// listResponse returns a page of a list the fake returned, with its page URLs moved from Lob to
+// the server.
+func (s *Server) listResponse(data interface{}, count int, nextURL, previousURL string) listResponse {
+	pageURL := func(u string) *string {
+		if u == "" {
+			return nil
+		}
+		u = s.BaseAPI() + strings.TrimPrefix(u, lob.BaseAPI)
+		return &u
+	}
+	return listResponse{
+		Data:        data,
+		Object:      "list",
+		NextURL:     pageURL(nextURL),
+		PreviousURL: pageURL(previousURL),
+		Count:       count,
+	}
+}
+
+// Addresses
+
+func (s *Server) createAddress(w http.ResponseWriter, r *request) {
+	address := new(lob.Address)
+	if err := s.decode(r, address); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Lob assigns the ID and dates of new addresses, whatever the request says.
+	address.ID = ""
+	address.DateCreated = time.Time{}
+	created, err := s.Fake.CreateAddress(address)
+	writeResult(w, created, err)
+}
+
+func (s *Server) getAddress(w http.ResponseWriter, id string) {
+	address, err := s.Fake.GetAddress(id)
+	writeResult(w, address, err)
+}
+
+type deleteResponse struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+func (s *Server) deleteAddress(w http.ResponseWriter, id string) {
+	err := s.Fake.DeleteAddress(id)
+	writeResult(w, deleteResponse{ID: id, Deleted: true}, err)
+}
+
+func (s *Server) listAddresses(w http.ResponseWriter, r *request) {
+	list, err := s.Fake.ListAddressesPage(r.URL.String())
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	writeJSON(w, s.listResponse(list.Data, list.Count, list.NextURL, list.PreviousURL))
+}
+
+func (s *Server) verifyUSAddress(w http.ResponseWriter, r *request) {
+	req := new(lob.USAddressVerificationRequest)
+	if err := s.decode(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	address := &lob.Address{
+		Name:         req.Recipient,
+		AddressLine2: req.AddressLine2,
+		AddressCity:  req.AddressCity,
+		AddressState: req.AddressState,
+		AddressZip:   req.AddressZip,
+	}
+	if req.AddressLine1 != nil {
+		address.AddressLine1 = *req.AddressLine1
+	}
+	resp, err := s.Fake.VerifyUSAddress(address)
+	writeResult(w, resp, err)
+}
+
+func (s *Server) getStates(w http.ResponseWriter, r *request) {
+	states, err := s.Fake.GetStates()
+	writeResult(w, states, err)
+}
+
+func (s *Server) getCountries(w http.ResponseWriter, r *request) {
+	countries, err := s.Fake.GetCountries()
+	writeResult(w, countries, err)
+}
+
+// Bank accounts
+
+func (s *Server) createBankAccount(w http.ResponseWriter, r *request) {
+	req := new(lob.CreateBankAccountRequest)
+	if err := s.decode(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	bankAccount, err := s.Fake.CreateBankAccount(req)
+	writeResult(w, bankAccount, err)
+}
+
+func (s *Server) getBankAccount(w http.ResponseWriter, id string) {
+	bankAccount, err := s.Fake.GetBankAccount(id)
+	writeResult(w, bankAccount, err)
+}
+
+func (s *Server) listBankAccounts(w http.ResponseWriter, r *request) {
+	list, err := s.Fake.ListBankAccountsPage(r.URL.String())
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	writeJSON(w, s.listResponse(list.Data, list.Count, list.NextURL, list.PreviousURL))
+}
+
+// Checks
+
+func (s *Server) createCheck(w http.ResponseWriter, r *request) {
+	req := new(lob.CreateCheckRequest)
+	if err := s.decode(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	check, err := s.Fake.CreateCheck(req)
+	if err == nil {
+		check = checkForVersion(check, r.version)
+	}
+	writeResult(w, check, err)
+}
+
+// checkForVersion returns the check as the given API version describes it: with data before
+// 2020-02-11 and merge_variables from then on. The fake fills in both.
+func checkForVersion(check *lob.Check, version string) *lob.Check {
+	c := *check
+	if version < lob.APIVersion20200211 {
+		c.MergeVariables = nil
+	} else {
+		c.Data = nil
+	}
+	return &c
+}
+
+func (s *Server) getCheck(w http.ResponseWriter, r *request, id string) {
+	check, err := s.Fake.GetCheck(id)
+	if err == nil {
+		check = checkForVersion(check, r.version)
+	}
+	writeResult(w, check, err)
+}
+
+func (s *Server) listChecks(w http.ResponseWriter, r *request) {
+	list, err := s.Fake.ListChecksPage(r.URL.String())
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	for i := range list.Data {
+		list.Data[i] = *checkForVersion(&list.Data[i], r.version)
+	}
+	writeJSON(w, s.listResponse(list.Data, list.Count, list.NextURL, list.PreviousURL))
+}
+
+func (s *Server) cancelCheck(w http.ResponseWriter, id string) {
+	resp, err := s.Fake.CancelCheck(id)
+	writeResult(w, resp, err)
+}
@@ -0,0 +1,326 @@
+// Package lobtest provides a simulator of the Lob API for tests. Unlike the fake returned by
+// lob.NewFakeLob, the simulator is an HTTP server, so requests go through the real client:
+// encoding, authentication, headers and error decoding are all exercised.
+//
+//	server := lobtest.NewServer()
+//	defer server.Close()
+//	client := lob.NewLob(server.BaseAPI(), lobtest.TestAPIKey, "my-app tests")
+package lobtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lob "github.com/seedco/go-lob"
+)
+
+// TestAPIKey is the API key a new Server accepts.
+const TestAPIKey = "test_lobtest"
+
+// RateLimit is the number of requests per minute the server reports in its rate limit headers.
+// The limit is reported but not enforced.
+const RateLimit = 150
+
+// supportedVersions are the Lob-Version values the server accepts.
+var supportedVersions = map[string]bool{
+	lob.APIVersion20190601: true,
+	lob.APIVersion20200211: true,
+}
+
+// Server is a Lob API served over HTTP. It answers requests with calls to a lob.FakeLob, which
+// keeps addresses, bank accounts and checks between requests and validates them, and translates
+// the results to Lob's payloads and error format. Its exported fields should be set before the
+// first request.
+type Server struct {
+	*httptest.Server
+
+	// APIKeys are the keys the server accepts. NewServer accepts TestAPIKey.
+	APIKeys []string
+	// Fake stores what the server is sent. Its Now and CancellationWindow are the server's.
+	Fake *lob.FakeLob
+	// Faults, if set, injects failures into requests. Faults are injected by the name of the Lob
+	// method a request is for, e.g. "CreateCheck".
+	Faults *lob.Faults
+
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	mu          sync.Mutex
+	window      time.Time
+	windowCount int
+}
+
+// NewServer starts a simulator with no stored objects.
+func NewServer() *Server {
+	s := &Server{
+		APIKeys: []string{TestAPIKey},
+		Fake:    lob.NewFakeLob(),
+		closed:  make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Advance moves the server's time forward by d, e.g. to move checks past their send date. Unlike
+// setting Fake.Now, it is safe while the server is in use.
+func (s *Server) Advance(d time.Duration) {
+	s.Fake.Advance(d)
+}
+
+// BaseAPI returns the base URL to create clients with, in place of lob.BaseAPI.
+func (s *Server) BaseAPI() string {
+	return s.URL + "/v1/"
+}
+
+// apiError is Lob's error response.
+type apiError struct {
+	Error lob.Error `json:"error"`
+}
+
+// request is a request to the simulator after authentication.
+type request struct {
+	*http.Request
+	// path is the path under /v1/, split into segments.
+	path    []string
+	version string
+}
+
+// ServeHTTP serves the Lob API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Request-Id", "req_"+newID())
+	s.mu.Lock()
+	s.setRateLimitHeaders(w.Header())
+	s.mu.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, "/v1/") {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+		return
+	}
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "Your API key is not valid. Please sign up on lob.com to get a valid api key.")
+		return
+	}
+	version := r.Header.Get("Lob-Version")
+	if version == "" {
+		version = lob.APIVersion
+	}
+	if !supportedVersions[version] {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%q is not a valid Lob-Version", version))
+		return
+	}
+
+	req := &request{
+		Request: r,
+		path:    strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/"), "/"), "/"),
+		version: version,
+	}
+	endpoint, handler := s.route(req)
+	if handler == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+		return
+	}
+	s.serve(w, req, s.Faults.Next(endpoint), handler)
+}
+
+// Close shuts down the server, ending any requests held open by timeout faults.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+	s.Server.Close()
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	key, _, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	for _, k := range s.APIKeys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// setRateLimitHeaders reports the requests made in the current minute against RateLimit.
+func (s *Server) setRateLimitHeaders(h http.Header) {
+	window := time.Now().Truncate(time.Minute)
+	if !window.Equal(s.window) {
+		s.window = window
+		s.windowCount = 0
+	}
+	s.windowCount++
+	remaining := RateLimit - s.windowCount
+	if remaining < 0 {
+		remaining = 0
+	}
+	h.Set("X-Rate-Limit-Limit", strconv.Itoa(RateLimit))
+	h.Set("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+	h.Set("X-Rate-Limit-Reset", strconv.FormatInt(window.Add(time.Minute).Unix(), 10))
+}
+
+// route returns the name of the Lob method a request is for, which faults are injected by, and
+// its handler, or nil if the route does not exist.
+func (s *Server) route(r *request) (string, func(http.ResponseWriter, *request)) {
+	resource, id := r.path[0], ""
+	if len(r.path) == 2 {
+		id = r.path[1]
+	} else if len(r.path) > 2 {
+		return "", nil
+	}
+	collection := id == ""
+
+	switch {
+	case resource == "addresses" && collection && r.Method == "POST":
+		return "CreateAddress", s.createAddress
+	case resource == "addresses" && collection && r.Method == "GET":
+		return "ListAddresses", s.listAddresses
+	case resource == "addresses" && r.Method == "GET":
+		return "GetAddress", func(w http.ResponseWriter, r *request) { s.getAddress(w, id) }
+	case resource == "addresses" && r.Method == "DELETE":
+		return "DeleteAddress", func(w http.ResponseWriter, r *request) { s.deleteAddress(w, id) }
+
+	case resource == "bank_accounts" && collection && r.Method == "POST":
+		return "CreateBankAccount", s.createBankAccount
+	case resource == "bank_accounts" && collection && r.Method == "GET":
+		return "ListBankAccounts", s.listBankAccounts
+	case resource == "bank_accounts" && r.Method == "GET":
+		return "GetBankAccount", func(w http.ResponseWriter, r *request) { s.getBankAccount(w, id) }
+
+	case resource == "checks" && collection && r.Method == "POST":
+		return "CreateCheck", s.createCheck
+	case resource == "checks" && collection && r.Method == "GET":
+		return "ListChecks", s.listChecks
+	case resource == "checks" && r.Method == "GET":
+		return "GetCheck", func(w http.ResponseWriter, r *request) { s.getCheck(w, r, id) }
+	case resource == "checks" && r.Method == "DELETE":
+		return "CancelCheck", func(w http.ResponseWriter, r *request) { s.cancelCheck(w, id) }
+
+	case resource == "us_verifications" && collection && r.Method == "POST":
+		return "VerifyUSAddress", s.verifyUSAddress
+	case resource == "states" && collection && r.Method == "GET":
+		return "GetStates", s.getStates
+	case resource == "countries" && collection && r.Method == "GET":
+		return "GetCountries", s.getCountries
+	}
+	return "", nil
+}
+
+// serve handles the request with any fault injected.
+func (s *Server) serve(w http.ResponseWriter, r *request, fault lob.Fault, handler func(http.ResponseWriter, *request)) {
+	time.Sleep(fault.Latency)
+	if fault.StatusCode != 0 {
+		if fault.StatusCode == http.StatusTooManyRequests {
+			w.Header().Set("X-Rate-Limit-Remaining", "0")
+		}
+		writeError(w, fault.StatusCode, fault.ErrorMessage())
+		return
+	}
+	if !fault.Timeout && !fault.Malformed {
+		handler(w, r)
+		return
+	}
+
+	// The request is handled, but its response is lost.
+	rec := httptest.NewRecorder()
+	handler(rec, r)
+	if fault.Timeout {
+		select {
+		case <-r.Context().Done():
+		case <-s.closed:
+		}
+		return
+	}
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	body := rec.Body.Bytes()
+	w.Write(body[:len(body)/2])
+}
+
+// decode reads a JSON, URL encoded or multipart request body into v. Form fields named the way
+// Lob names them, e.g. to[address_line1], are decoded as nested objects, and uploaded files as
+// URLs on the simulator named after the upload.
+func (s *Server) decode(r *request, v interface{}) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		return json.NewDecoder(r.Body).Decode(v)
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return err
+		}
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported content type %q", mediaType)
+	}
+
+	fields := make(map[string]interface{})
+	for key, values := range r.PostForm {
+		setFormValue(fields, key, values[0])
+	}
+	if r.MultipartForm != nil {
+		for key, files := range r.MultipartForm.File {
+			setFormValue(fields, key, s.URL+"/files/"+newID()+"/"+url.PathEscape(files[0].Filename))
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// setFormValue sets a form field such as to[address_line1] in nested maps.
+func setFormValue(fields map[string]interface{}, key, value string) {
+	name, rest := key, ""
+	if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+		name, rest = key[:i], key[i+1:len(key)-1]
+	}
+	if rest == "" {
+		fields[name] = value
+		return
+	}
+	nested, ok := fields[name].(map[string]interface{})
+	if !ok {
+		nested = make(map[string]interface{})
+		fields[name] = nested
+	}
+	// rest is e.g. "a][b", the remaining bracketed keys without their outer brackets.
+	if i := strings.Index(rest, "]["); i >= 0 {
+		setFormValue(nested, rest[:i]+"["+rest[i+2:]+"]", value)
+	} else {
+		nested[rest] = value
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiError{Error: lob.Error{Message: message, StatusCode: status}})
+}
+
+// newID returns 16 random hex digits, the form of the IDs Lob generates.
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,319 @@
+package lobtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	lob "github.com/seedco/go-lob"
+)
+
+const testUserAgent = "go-lob lobtest"
+
+func TestServerEndToEnd(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := lob.NewLob(server.BaseAPI(), TestAPIKey, testUserAgent)
+
+	from, err := client.CreateAddress(testAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(from.ID, "adr_") || from.DateCreated.IsZero() {
+		t.Errorf("Expected a stored address, got %+v", from)
+	}
+	if got, err := client.GetAddress(from.ID); err != nil || got.AddressLine1 != "1005 W Burnside St" {
+		t.Errorf("Expected to get the address back, got %+v, %v", got, err)
+	}
+
+	bankAccount, err := client.CreateBankAccount(testBankAccount())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp lob.Response
+	check, err := client.WithResponse(&resp).CreateCheck(&lob.CreateCheckRequest{
+		Amount:        lob.MustParseMoney("987.65"),
+		BankAccountID: bankAccount.ID,
+		From:          lob.AddressID(from.ID),
+		To:            lob.InlineAddress(testAddress()),
+		Logo:          lob.FileUpload("logo.png", strings.NewReader("png")),
+		Memo:          stringPtr("rent"),
+		Data:          map[string]string{"name": "Harry"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Amount != lob.MustParseMoney("987.65") || check.To == nil || check.To.ID == from.ID || check.Memo != "rent" {
+		t.Errorf("Expected the check with a new inline address, got %+v", check)
+	}
+	if check.Logo == nil || !strings.HasSuffix(*check.Logo, "/logo.png") {
+		t.Errorf("Expected the uploaded logo, got %v", check.Logo)
+	}
+	if check.Data["name"] != "Harry" || check.CheckNumber != 10000 {
+		t.Errorf("Expected data and a check number, got %+v", check)
+	}
+	if resp.RequestID == "" || resp.RateLimit.Limit != RateLimit {
+		t.Errorf("Expected a request ID and rate limit, got %+v", resp)
+	}
+
+	if got, err := client.GetCheck(check.ID); err != nil || got.ID != check.ID {
+		t.Errorf("Expected to get the check back, got %+v, %v", got, err)
+	}
+	list, err := client.ListChecks(10)
+	if err != nil || len(list.Data) != 1 || list.Count != 1 {
+		t.Errorf("Expected one check to be listed, got %+v, %v", list, err)
+	}
+	if cancelled, err := client.CancelCheck(check.ID); err != nil || !cancelled.Deleted {
+		t.Errorf("Expected the check to be cancelled, got %+v, %v", cancelled, err)
+	}
+	if list, _ := client.ListChecks(10); len(list.Data) != 0 {
+		t.Errorf("Expected cancelled checks not to be listed, got %+v", list)
+	}
+
+	addresses, err := client.ListAddresses(10)
+	if err != nil || len(addresses.Data) != 2 || addresses.Data[0].ID != check.To.ID {
+		t.Errorf("Expected both addresses, newest first, got %+v, %v", addresses, err)
+	}
+	if err := client.DeleteAddress(from.ID); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := client.GetAddress(from.ID); err != nil || deleted.Deleted == nil || !*deleted.Deleted {
+		t.Errorf("Expected the deleted address to be marked deleted, got %+v, %v", deleted, err)
+	}
+	if addresses, _ := client.ListAddresses(10); len(addresses.Data) != 1 {
+		t.Errorf("Expected deleted addresses not to be listed, got %+v", addresses)
+	}
+	if err := client.DeleteAddress(from.ID); !errors.Is(err, lob.ErrNotFound) {
+		t.Errorf("Expected deleting an address again to be a 404, got %v", err)
+	}
+
+	verification, err := client.VerifyUSAddress(testAddress())
+	if err != nil || verification.Deliverability != "deliverable" || verification.PrimaryLine != "1005 W BURNSIDE ST" {
+		t.Errorf("Expected a deliverable address, got %+v, %v", verification, err)
+	}
+
+	states, err := client.GetStates()
+	if err != nil || len(states.Data) < 50 {
+		t.Errorf("Expected the US states, got %+v, %v", states, err)
+	}
+	countries, err := client.GetCountries()
+	if err != nil || len(countries.Data) < 200 {
+		t.Errorf("Expected the countries, got %d, %v", len(countries.Data), err)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := lob.NewLob(server.BaseAPI(), TestAPIKey, testUserAgent)
+
+	tests := []struct {
+		name   string
+		call   func() error
+		status int
+	}{
+		{"wrong key", func() error {
+			_, err := lob.NewLob(server.BaseAPI(), "test_wrong", testUserAgent).GetStates()
+			return err
+		}, http.StatusUnauthorized},
+		{"unsupported version", func() error {
+			_, err := client.WithAPIVersion("2001-01-01").GetStates()
+			return err
+		}, http.StatusUnprocessableEntity},
+		{"missing check", func() error {
+			_, err := client.GetCheck("chk_missing")
+			return err
+		}, http.StatusNotFound},
+		{"missing bank account", func() error {
+			_, err := client.CreateCheck(&lob.CreateCheckRequest{
+				Amount:        lob.MustParseMoney("1"),
+				BankAccountID: "bank_missing",
+				From:          lob.InlineAddress(testAddress()),
+				To:            lob.InlineAddress(testAddress()),
+			})
+			return err
+		}, http.StatusUnprocessableEntity},
+	}
+	for _, test := range tests {
+		var apiErr *lob.APIError
+		if err := test.call(); !errors.As(err, &apiErr) || apiErr.StatusCode != test.status {
+			t.Errorf("%s: expected status %d, got %v", test.name, test.status, err)
+		}
+	}
+
+	address, err := client.GetAddress("adr_missing")
+	if err == nil || address.Error == nil || address.Error.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected Lob's error to be decoded, got %+v", address)
+	}
+}
+
+func TestServerCancellationWindow(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	server.Fake.Now = func() time.Time { return now }
+	client := lob.NewLob(server.BaseAPI(), TestAPIKey, testUserAgent)
+
+	bankAccount, err := client.CreateBankAccount(testBankAccount())
+	if err != nil {
+		t.Fatal(err)
+	}
+	check, err := client.CreateCheck(&lob.CreateCheckRequest{
+		Amount:        lob.MustParseMoney("1"),
+		BankAccountID: bankAccount.ID,
+		From:          lob.InlineAddress(testAddress()),
+		To:            lob.InlineAddress(testAddress()),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.SendDate.Equal(now.Add(lob.DefaultCancellationWindow)) {
+		t.Errorf("Expected the send date to end the cancellation window, got %s", check.SendDate)
+	}
+
+	now = now.Add(lob.DefaultCancellationWindow)
+	var apiErr *lob.APIError
+	if _, err := client.CancelCheck(check.ID); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity || !errors.Is(err, lob.ErrCheckNotCancellable) {
+		t.Errorf("Expected a sent check not to be cancellable, got %v", err)
+	}
+}
+
+func TestServerAPIVersions(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := lob.NewLob(server.BaseAPI(), TestAPIKey, testUserAgent)
+
+	bankAccount, err := client.CreateBankAccount(testBankAccount())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &lob.CreateCheckRequest{
+		Amount:         lob.MustParseMoney("1"),
+		BankAccountID:  bankAccount.ID,
+		From:           lob.InlineAddress(testAddress()),
+		To:             lob.InlineAddress(testAddress()),
+		MergeVariables: map[string]interface{}{"name": "Harry"},
+	}
+	for _, version := range []string{lob.APIVersion20190601, lob.APIVersion20200211} {
+		var resp lob.Response
+		check, err := client.WithAPIVersion(version).WithResponse(&resp).CreateCheck(req)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		if check.Data["name"] != "Harry" || check.MergeVariables["name"] != "Harry" {
+			t.Errorf("%s: expected merge variables either way, got %+v", version, check)
+		}
+		field := `"merge_variables":{`
+		if version == lob.APIVersion20190601 {
+			field = `"data":{`
+		}
+		if !strings.Contains(string(resp.Body), field) {
+			t.Errorf("%s: expected %s in the response, got %s", version, field, resp.Body)
+		}
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Faults = lob.NewFaults()
+	client := lob.NewLob(server.BaseAPI(), TestAPIKey, testUserAgent)
+
+	bankAccount, err := client.CreateBankAccount(testBankAccount())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &lob.CreateCheckRequest{
+		Amount:        lob.MustParseMoney("1"),
+		BankAccountID: bankAccount.ID,
+		From:          lob.InlineAddress(testAddress()),
+		To:            lob.InlineAddress(testAddress()),
+	}
+
+	server.Faults.FailNext("CreateCheck", 2, lob.Fault{StatusCode: http.StatusTooManyRequests})
+	for i := 0; i < 2; i++ {
+		var resp lob.Response
+		var apiErr *lob.APIError
+		if _, err := client.WithResponse(&resp).CreateCheck(req); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("Expected a 429, got %v", err)
+		}
+		if resp.RateLimit.Remaining != 0 {
+			t.Errorf("Expected no remaining requests, got %d", resp.RateLimit.Remaining)
+		}
+	}
+	if _, err := client.CreateCheck(req); err != nil {
+		t.Errorf("Expected the third call to succeed, got %v", err)
+	}
+
+	server.Faults.FailNext("CreateCheck", 1, lob.Fault{Malformed: true})
+	if _, err := client.CreateCheck(req); err == nil {
+		t.Error("Expected a malformed response to fail")
+	}
+
+	server.Faults.FailNext("CreateCheck", 1, lob.Fault{Timeout: true})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.WithContext(ctx).CreateCheck(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+
+	server.Faults.SetRate("ListChecks", 1, lob.Fault{Latency: 20 * time.Millisecond})
+	start := time.Now()
+	list, err := client.ListChecks(10)
+	if err != nil || time.Since(start) < 20*time.Millisecond {
+		t.Errorf("Expected a slow response, got %v after %s", err, time.Since(start))
+	}
+	if len(list.Data) != 3 {
+		t.Errorf("Expected lost responses to still create checks, got %d", len(list.Data))
+	}
+}
+
+func TestServerListPages(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := lob.NewLob(server.BaseAPI(), TestAPIKey, testUserAgent)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		address, err := client.CreateAddress(testAddress())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, address.ID)
+	}
+
+	list, err := client.ListAddresses(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Count != 2 || list.Data[0].ID != ids[4] || list.Data[1].ID != ids[3] || list.PreviousURL != "" {
+		t.Errorf("Expected the first page, got %+v", list)
+	}
+
+	page := func(pageURL string) *lob.ListAddressesResponse {
+		list, err := client.ListAddressesPage(pageURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return list
+	}
+	second := page(list.NextURL)
+	if second.Count != 2 || second.Data[0].ID != ids[2] || second.Data[1].ID != ids[1] {
+		t.Errorf("Expected the second page, got %+v", second)
+	}
+	last := page(second.NextURL)
+	if last.Count != 1 || last.Data[0].ID != ids[0] || last.NextURL != "" {
+		t.Errorf("Expected the last page, got %+v", last)
+	}
+	if previous := page(last.PreviousURL); previous.Count != 2 || previous.Data[0].ID != ids[2] {
+		t.Errorf("Expected the previous page, got %+v", previous)
+	}
+
+	if _, err := client.ListAddresses(101); err == nil {
+		t.Error("Expected listing more than 100 addresses to fail")
+	}
+}
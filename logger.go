@@ -0,0 +1,99 @@
+package lob
+
+import (
+	"strings"
+	"time"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+// Log levels, from least to most severe.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return "UNKNOWN"
+}
+
+// Field is a structured value attached to a log entry, such as the status of a response.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger receives a log entry for every request the client makes. Entries carry the fields
+// method, endpoint, status, latency, attempt and, when Lob sends one, request_id, plus error
+// for failed requests. attempt is 1 unless the client was made with WithAttempt. Successful requests are logged at LevelDebug, requests Lob rejects at
+// LevelInfo, and server errors and requests that could not be made at LevelError.
+// Implementations must be safe for concurrent use.
+type Logger interface {
+	Log(level Level, msg string, fields ...Field)
+}
+
+// LoggerFunc adapts a function to Logger.
+type LoggerFunc func(level Level, msg string, fields ...Field)
+
+// Log calls f.
+func (f LoggerFunc) Log(level Level, msg string, fields ...Field) {
+	f(level, msg, fields...)
+}
+
+// nopLogger discards everything; it is the client's logger unless one is set.
+type nopLogger struct{}
+
+func (nopLogger) Log(Level, string, ...Field) {}
+
+// logger returns the client's logger.
+func (l *lob) logger() Logger {
+	if l.Logger == nil {
+		return nopLogger{}
+	}
+	return l.Logger
+}
+
+// requestIDHeader is the response header Lob identifies requests with.
+const requestIDHeader = "X-Request-Id"
+
+// logRequest logs the outcome of a request. status is 0 if no response was received.
+func (l *lob) logRequest(method, endpoint string, status int, requestID string, latency time.Duration, err error) {
+	fields := []Field{
+		{Key: "method", Value: method},
+		{Key: "endpoint", Value: strings.SplitN(endpoint, "?", 2)[0]},
+		{Key: "status", Value: status},
+		{Key: "latency", Value: latency},
+		{Key: "attempt", Value: l.attemptNumber()},
+	}
+	if requestID != "" {
+		fields = append(fields, Field{Key: "request_id", Value: requestID})
+	}
+
+	level := LevelDebug
+	switch {
+	case status == 0 && err != nil, status >= 500:
+		level = LevelError
+	case status >= 400:
+		level = LevelInfo
+	case err != nil:
+		// The response could not be decoded.
+		level = LevelError
+	}
+	if err != nil {
+		fields = append(fields, Field{Key: "error", Value: err.Error()})
+	}
+	l.logger().Log(level, "lob request", fields...)
+}
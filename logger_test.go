@@ -0,0 +1,98 @@
+package lob
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	level  Level
+	msg    string
+	fields map[string]interface{}
+}
+
+// recordingLogger keeps every entry logged to it.
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (r *recordingLogger) Log(level Level, msg string, fields ...Field) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := logEntry{level: level, msg: msg, fields: make(map[string]interface{})}
+	for _, f := range fields {
+		entry.fields[f.Key] = f.Value
+	}
+	r.entries = append(r.entries, entry)
+}
+
+func TestLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_123")
+		switch r.URL.Path {
+		case "/addresses/adr_missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/addresses/adr_broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	l := NewLob(server.URL+"/", "test_key", testUserAgent)
+	if _, err := l.GetAddress("adr_123"); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := new(recordingLogger)
+	l.Logger = logger
+	l.GetAddress("adr_123")
+	l.GetAddress("adr_missing")
+	l.GetAddress("adr_broken")
+	l.WithAccount("unknown").GetAddress("adr_123")
+
+	expected := []struct {
+		level  Level
+		status int
+	}{
+		{LevelDebug, 200},
+		{LevelInfo, 404},
+		{LevelError, 500},
+		{LevelError, 0},
+	}
+	if len(logger.entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), logger.entries)
+	}
+	for i, e := range expected {
+		entry := logger.entries[i]
+		if entry.level != e.level || entry.fields["status"] != e.status {
+			t.Errorf("Entry %d: expected %s with status %d, got %s with %v", i, e.level, e.status, entry.level, entry.fields["status"])
+		}
+		if entry.fields["method"] != "GET" || entry.fields["attempt"] != 1 {
+			t.Errorf("Entry %d: expected the method and attempt, got %v", i, entry.fields)
+		}
+		if _, ok := entry.fields["latency"].(time.Duration); !ok {
+			t.Errorf("Entry %d: expected a latency, got %v", i, entry.fields)
+		}
+	}
+
+	first := logger.entries[0].fields
+	if first["endpoint"] != "addresses/adr_123" || first["request_id"] != "req_123" {
+		t.Errorf("Expected the endpoint and request ID, got %v", first)
+	}
+	if _, ok := first["error"]; ok {
+		t.Errorf("Expected no error for a successful request, got %v", first)
+	}
+	if _, ok := logger.entries[1].fields["error"]; !ok {
+		t.Errorf("Expected an error for a failed request, got %v", logger.entries[1].fields)
+	}
+
+	l.WithAttempt(3).GetAddress("adr_123")
+	if last := logger.entries[len(logger.entries)-1]; last.fields["attempt"] != 3 {
+		t.Errorf("Expected a third attempt, got %v", last.fields)
+	}
+}
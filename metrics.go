@@ -0,0 +1,127 @@
+package lob
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives a measurement of every request the client makes. Implementations must be
+// safe for concurrent use.
+type Metrics interface {
+	ObserveRequest(RequestMetrics)
+}
+
+// RequestMetrics describes one attempt at a request to Lob.
+type RequestMetrics struct {
+	Method string
+	// Endpoint is the endpoint with IDs replaced by ":id", e.g. "checks/:id", so that it can be
+	// used as a metric label.
+	Endpoint string
+	// Status is the status code of the response, or 0 if no response was received.
+	Status  int
+	Latency time.Duration
+	// Attempt is 1 for the first attempt at a request and counts up for retries, as set by
+	// WithAttempt.
+	Attempt int
+	// Failed reports whether the request returned an error, including errors for 200 responses
+	// that could not be decoded.
+	Failed bool
+}
+
+// endpointActions are the path segments after an ID that name an action rather than another ID.
+var endpointActions = map[string]bool{
+	"verify": true,
+}
+
+// normalizeEndpoint strips the query and trailing slash from an endpoint and replaces the IDs in
+// it with ":id". The first segment is always a resource name, and later ones are IDs unless they
+// name an action, as in "bank_accounts/:id/verify".
+func normalizeEndpoint(endpoint string) string {
+	endpoint = strings.Trim(strings.SplitN(endpoint, "?", 2)[0], "/")
+	segments := strings.Split(endpoint, "/")
+	for i := 1; i < len(segments); i++ {
+		if !endpointActions[segments[i]] {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// WithAttempt returns a copy of the client whose requests are measured and logged as the given
+// attempt at a call: 1 for the first attempt and counting up for retries. Retry loops should set
+// it per attempt, e.g. l.WithAttempt(attempt).CreateCheck(req). Requests are attempt 1 otherwise.
+func (l *lob) WithAttempt(attempt int) *lob {
+	c := *l
+	c.attempt = attempt
+	return &c
+}
+
+// attemptNumber returns the attempt requests are measured and logged as.
+func (l *lob) attemptNumber() int {
+	if l.attempt < 1 {
+		return 1
+	}
+	return l.attempt
+}
+
+// recordMetrics reports a finished request to the client's Metrics, if it has any.
+func (l *lob) recordMetrics(method, endpoint string, status int, latency time.Duration, err error) {
+	if l.Metrics == nil {
+		return
+	}
+	l.Metrics.ObserveRequest(RequestMetrics{
+		Method:   method,
+		Endpoint: normalizeEndpoint(endpoint),
+		Status:   status,
+		Latency:  latency,
+		Attempt:  l.attemptNumber(),
+		Failed:   err != nil,
+	})
+}
+
+// MemoryMetrics records requests in memory, for tests and debugging.
+type MemoryMetrics struct {
+	mu       sync.Mutex
+	requests []RequestMetrics
+}
+
+// NewMemoryMetrics returns an empty MemoryMetrics.
+func NewMemoryMetrics() *MemoryMetrics {
+	return new(MemoryMetrics)
+}
+
+// ObserveRequest records r.
+func (m *MemoryMetrics) ObserveRequest(r RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, r)
+}
+
+// Requests returns every request recorded so far, oldest first.
+func (m *MemoryMetrics) Requests() []RequestMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RequestMetrics(nil), m.requests...)
+}
+
+// Count returns the number of requests recorded for the normalized endpoint with the given
+// status.
+func (m *MemoryMetrics) Count(endpoint string, status int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, r := range m.requests {
+		if r.Endpoint == endpoint && r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset discards every recorded request.
+func (m *MemoryMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = nil
+}
@@ -0,0 +1,62 @@
+package lob
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"checks/":                                   "checks",
+		"checks?limit=10":                           "checks",
+		"checks/chk_534f10783683daa0":               "checks/:id",
+		"addresses/adr_d3489cd64c791ab5":            "addresses/:id",
+		"us_verifications":                          "us_verifications",
+		"bank_accounts/bank_8cad8df5354d33f/verify": "bank_accounts/:id/verify",
+	}
+	for endpoint, expected := range tests {
+		if normalized := normalizeEndpoint(endpoint); normalized != expected {
+			t.Errorf("%s: expected %s, got %s", endpoint, expected, normalized)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/checks/chk_missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	metrics := NewMemoryMetrics()
+	l := NewLob(server.URL+"/", "test_key", testUserAgent)
+	l.Metrics = metrics
+	l.GetCheck("chk_123")
+	l.GetCheck("chk_missing")
+	l.WithAttempt(2).ListChecks(5)
+
+	if n := metrics.Count("checks/:id", 200); n != 1 {
+		t.Errorf("Expected 1 successful check lookup, got %d", n)
+	}
+	if n := metrics.Count("checks/:id", 404); n != 1 {
+		t.Errorf("Expected 1 missing check, got %d", n)
+	}
+	requests := metrics.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %v", requests)
+	}
+	if r := requests[1]; r.Method != "GET" || !r.Failed || r.Attempt != 1 {
+		t.Errorf("Expected a failed first attempt, got %+v", r)
+	}
+	if r := requests[2]; r.Endpoint != "checks" || r.Failed || r.Latency <= 0 || r.Attempt != 2 {
+		t.Errorf("Expected a timed second attempt at a list request, got %+v", r)
+	}
+
+	metrics.Reset()
+	if len(metrics.Requests()) != 0 {
+		t.Error("Expected Reset to discard requests")
+	}
+}
@@ -0,0 +1,151 @@
+package lob
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Errors returned for amounts Lob cannot print exactly.
+var (
+	ErrInvalidAmount          = errors.New("invalid amount")
+	ErrSubCentAmount          = errors.New("amount has fractions of a cent")
+	ErrCheckAmountNotPositive = errors.New("check amount must be greater than zero")
+	ErrCheckAmountTooLarge    = errors.New("check amount is larger than Lob allows")
+)
+
+// MaxCheckAmount is the largest amount Lob will print on a check.
+var MaxCheckAmount = MoneyFromCents(99999999)
+
+var amountPattern = regexp.MustCompile(`^(-?)(\d+)(?:\.(\d+))?$`)
+
+// Money is an exact amount of US dollars, kept as a whole number of cents so that amounts are
+// never rounded on their way to Lob. It encodes to and from Lob's decimal amount format, e.g.
+// 987.65. The zero value is $0.00.
+type Money struct {
+	cents int64
+}
+
+// MoneyFromCents returns the amount of the given number of cents.
+func MoneyFromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// ParseMoney parses a decimal dollar amount such as "987.65". Amounts with fractions of a cent,
+// such as "1234.005", are rejected rather than rounded.
+func ParseMoney(s string) (Money, error) {
+	m := amountPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	fraction := strings.TrimRight(m[3], "0")
+	if len(fraction) > 2 {
+		return Money{}, fmt.Errorf("%w: %q", ErrSubCentAmount, s)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	dollars, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil || dollars > (math.MaxInt64-99)/100 {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+	total := dollars*100 + cents
+	if m[1] == "-" {
+		total = -total
+	}
+	return Money{cents: total}, nil
+}
+
+// MustParseMoney is like ParseMoney but panics if s is not a valid amount. It is meant for
+// constants in code and tests.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromFloat converts a float64 dollar amount, as used by earlier versions of this package,
+// to Money. It fails if the float is not a whole number of cents, such as 1234.005.
+func MoneyFromFloat(f float64) (Money, error) {
+	return ParseMoney(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Cents returns the amount as a number of cents.
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// IsZero reports whether the amount is $0.00.
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+// Add returns m + n.
+func (m Money) Add(n Money) Money {
+	return Money{cents: m.cents + n.cents}
+}
+
+// Sub returns m - n.
+func (m Money) Sub(n Money) Money {
+	return Money{cents: m.cents - n.cents}
+}
+
+// Cmp compares m and n, returning -1 if m < n, 0 if they are equal and +1 if m > n.
+func (m Money) Cmp(n Money) int {
+	switch {
+	case m.cents < n.cents:
+		return -1
+	case m.cents > n.cents:
+		return 1
+	}
+	return 0
+}
+
+// String formats the amount in Lob's format, e.g. "987.65".
+func (m Money) String() string {
+	sign := ""
+	cents := m.cents
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// ValidateCheckAmount returns an error if the amount cannot be printed on a check.
+func (m Money) ValidateCheckAmount() error {
+	if m.cents <= 0 {
+		return ErrCheckAmountNotPositive
+	}
+	if m.Cmp(MaxCheckAmount) > 0 {
+		return fmt.Errorf("%w: %s is more than %s", ErrCheckAmountTooLarge, m, MaxCheckAmount)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or string amount exactly.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
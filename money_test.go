@@ -0,0 +1,95 @@
+package lob
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in    string
+		cents int64
+		out   string
+		err   error
+	}{
+		{in: "987.65", cents: 98765, out: "987.65"},
+		{in: "100", cents: 10000, out: "100.00"},
+		{in: "0.5", cents: 50, out: "0.50"},
+		{in: "1.500", cents: 150, out: "1.50"},
+		{in: "-12.01", cents: -1201, out: "-12.01"},
+		{in: "1234.005", err: ErrSubCentAmount},
+		{in: "0.001", err: ErrSubCentAmount},
+		{in: "", err: ErrInvalidAmount},
+		{in: "12.", err: ErrInvalidAmount},
+		{in: "1e3", err: ErrInvalidAmount},
+		{in: "$5", err: ErrInvalidAmount},
+		{in: "99999999999999999999", err: ErrInvalidAmount},
+	}
+
+	for _, test := range tests {
+		m, err := ParseMoney(test.in)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("%q: expected error %v, got %v", test.in, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", test.in, err)
+			continue
+		}
+		if m.Cents() != test.cents || m.String() != test.out {
+			t.Errorf("%q: expected %d cents (%s), got %d cents (%s)", test.in, test.cents, test.out, m.Cents(), m)
+		}
+	}
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	if m, err := MoneyFromFloat(987.65); err != nil || m.Cents() != 98765 {
+		t.Errorf("Expected 98765 cents, got %d (%v)", m.Cents(), err)
+	}
+	if _, err := MoneyFromFloat(1234.005); !errors.Is(err, ErrSubCentAmount) {
+		t.Errorf("Expected a sub-cent error, got %v", err)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var check Check
+	if err := json.Unmarshal([]byte(`{"amount": 1234.56}`), &check); err != nil {
+		t.Fatal(err)
+	}
+	if check.Amount.Cents() != 123456 {
+		t.Errorf("Expected 123456 cents, got %d", check.Amount.Cents())
+	}
+	if err := json.Unmarshal([]byte(`{"amount": "0.10"}`), &check); err != nil || check.Amount.Cents() != 10 {
+		t.Errorf("Expected 10 cents from a string amount, got %d (%v)", check.Amount.Cents(), err)
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 1.001}`), &check); !errors.Is(err, ErrSubCentAmount) {
+		t.Errorf("Expected a sub-cent error, got %v", err)
+	}
+
+	data, err := json.Marshal(CreateCheckRequest{Amount: MoneyFromCents(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":0.01}` {
+		t.Errorf("Expected amount to encode as 0.01, got %s", data)
+	}
+}
+
+func TestMoneyValidateCheckAmount(t *testing.T) {
+	if err := MustParseMoney("999999.99").ValidateCheckAmount(); err != nil {
+		t.Errorf("Expected the maximum amount to be valid, got %s", err)
+	}
+	if err := MustParseMoney("1000000").ValidateCheckAmount(); !errors.Is(err, ErrCheckAmountTooLarge) {
+		t.Errorf("Expected a too large error, got %v", err)
+	}
+	if err := (Money{}).ValidateCheckAmount(); !errors.Is(err, ErrCheckAmountNotPositive) {
+		t.Errorf("Expected a not positive error, got %v", err)
+	}
+
+	if _, err := NewFakeLob().CreateCheck(&CreateCheckRequest{Amount: MustParseMoney("1000000")}); !errors.Is(err, ErrCheckAmountTooLarge) {
+		t.Errorf("Expected the fake to reject the amount, got %v", err)
+	}
+}
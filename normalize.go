@@ -0,0 +1,675 @@
+package lob
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Errors returned when an address cannot be normalized offline.
+var (
+	ErrNotUSAddress         = errors.New("address is not a US address")
+	ErrMissingPrimaryNumber = errors.New("address_line1 has no primary number")
+	ErrMissingStreetName    = errors.New("address_line1 has no street name")
+	ErrUnknownState         = errors.New("unknown US state")
+	ErrInvalidZip           = errors.New("invalid ZIP code")
+	ErrIncompleteLastLine   = errors.New("address needs a ZIP code or both a city and a state")
+)
+
+var (
+	zipPattern      = regexp.MustCompile(`^(\d{5})(?:-?(\d{4}))?$`)
+	fractionPattern = regexp.MustCompile(`^\d+/\d+$`)
+)
+
+// NormalizeUSAddress returns a copy of address rewritten in USPS Publication 28 standard form:
+// upper case, standard street suffix, directional and secondary unit abbreviations, a two-letter
+// state code and a ZIP or ZIP+4 code. The input address is not modified. No API calls are made.
+func NormalizeUSAddress(address *Address) (*Address, error) {
+	components, extra, err := parseUSAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := *address
+	normalized.Error = nil
+	normalized.AddressLine1 = components.PrimaryLine()
+	normalized.AddressLine2 = nil
+	if line2 := joinNonEmpty(components.SecondaryLine(), extra); line2 != "" {
+		normalized.AddressLine2 = &line2
+	}
+	normalized.AddressCity = optionalString(components.City)
+	normalized.AddressState = optionalString(components.State)
+	normalized.AddressZip = optionalString(components.ZipCode)
+	if components.ZipCodePlus_4 != "" {
+		zip := components.ZipCode + "-" + components.ZipCodePlus_4
+		normalized.AddressZip = &zip
+	}
+	normalized.AddressCountry = optionalString("US")
+	if address.Name != nil {
+		normalized.Name = optionalString(collapseSpaces(*address.Name))
+	}
+	if address.Company != nil {
+		normalized.Company = optionalString(collapseSpaces(*address.Company))
+	}
+	return &normalized, nil
+}
+
+// ParseUSAddress splits a US address into its USPS components without calling the verification API.
+// Only the fields that can be derived from the text of the address are filled in.
+func ParseUSAddress(address *Address) (*USAddressComponents, error) {
+	components, _, err := parseUSAddress(address)
+	return components, err
+}
+
+// PrimaryLine renders the delivery address line, including any secondary unit, e.g.
+// "1005 W BURNSIDE ST STE 100".
+func (c *USAddressComponents) PrimaryLine() string {
+	if c.RecordType == "P" {
+		return joinNonEmpty("PO BOX", c.PrimaryNumber)
+	}
+	return joinNonEmpty(
+		c.PrimaryNumber,
+		c.StreetPredirection,
+		c.StreetName,
+		c.StreetSuffix,
+		c.StreetPostdirection,
+		c.SecondaryDesignator,
+		c.SecondaryNumber,
+	)
+}
+
+// SecondaryLine renders the private mailbox and any extra secondary unit, which USPS places
+// below the delivery address line.
+func (c *USAddressComponents) SecondaryLine() string {
+	return joinNonEmpty(
+		c.PmbDesignator,
+		c.PmbNumber,
+		c.ExtraSecondary_designator,
+		c.ExtraSecondary_number,
+	)
+}
+
+// LastLine renders the city, state and ZIP code line, e.g. "PORTLAND OR 97209-2844".
+func (c *USAddressComponents) LastLine() string {
+	zip := c.ZipCode
+	if zip != "" && c.ZipCodePlus_4 != "" {
+		zip += "-" + c.ZipCodePlus_4
+	}
+	return joinNonEmpty(c.City, c.State, zip)
+}
+
+// USStateCode returns the two-letter USPS code for a state, territory or military state given
+// either its name or its code, in any case.
+func USStateCode(state string) (string, bool) {
+	s := strings.ToUpper(collapseSpaces(strings.Replace(state, ".", "", -1)))
+	if _, ok := usStateNames[s]; ok {
+		return s, true
+	}
+	code, ok := usStateCodes[s]
+	return code, ok
+}
+
+// parseUSAddress does the work for ParseUSAddress and also returns any text from address_line2
+// that is not a recognized secondary unit, so NormalizeUSAddress can keep it.
+func parseUSAddress(address *Address) (*USAddressComponents, string, error) {
+	if address == nil {
+		return nil, "", ErrMissingPrimaryNumber
+	}
+	if address.AddressCountry != nil {
+		switch strings.ToUpper(strings.TrimSpace(*address.AddressCountry)) {
+		case "", "US", "USA", "UNITED STATES", "UNITED STATES OF AMERICA":
+		default:
+			return nil, "", ErrNotUSAddress
+		}
+	}
+
+	c := new(USAddressComponents)
+	tokens := addressTokens(address.AddressLine1)
+	if err := parseDeliveryLine(c, tokens); err != nil {
+		return nil, "", err
+	}
+
+	var extra string
+	if address.AddressLine2 != nil {
+		extra = parseSecondaryLine(c, addressTokens(*address.AddressLine2))
+	}
+
+	if address.AddressCity != nil {
+		c.City = strings.Join(addressTokens(*address.AddressCity), " ")
+	}
+	if address.AddressState != nil && strings.TrimSpace(*address.AddressState) != "" {
+		code, ok := USStateCode(*address.AddressState)
+		if !ok {
+			return nil, "", fmt.Errorf("%w: %q", ErrUnknownState, *address.AddressState)
+		}
+		c.State = code
+	}
+	if address.AddressZip != nil && strings.TrimSpace(*address.AddressZip) != "" {
+		m := zipPattern.FindStringSubmatch(strings.TrimSpace(*address.AddressZip))
+		if m == nil {
+			return nil, "", fmt.Errorf("%w: %q", ErrInvalidZip, *address.AddressZip)
+		}
+		c.ZipCode = m[1]
+		c.ZipCodePlus_4 = m[2]
+	}
+	if c.ZipCode == "" && (c.City == "" || c.State == "") {
+		return nil, "", ErrIncompleteLastLine
+	}
+	return c, extra, nil
+}
+
+// parseDeliveryLine fills in the primary number, street and secondary unit from address_line1.
+func parseDeliveryLine(c *USAddressComponents, tokens []string) error {
+	if n := poBoxPrefix(tokens); n > 0 {
+		if len(tokens) == n || !containsDigit(tokens[n]) {
+			return ErrMissingPrimaryNumber
+		}
+		c.RecordType = "P"
+		c.PrimaryNumber = tokens[n]
+		parseSecondaryLine(c, tokens[n+1:])
+		return nil
+	}
+
+	if len(tokens) == 0 || !containsDigit(tokens[0]) {
+		return ErrMissingPrimaryNumber
+	}
+	c.PrimaryNumber = tokens[0]
+	tokens = tokens[1:]
+	if len(tokens) > 0 && fractionPattern.MatchString(tokens[0]) {
+		c.PrimaryNumber += " " + tokens[0]
+		tokens = tokens[1:]
+	}
+
+	// Everything from the first secondary unit designator onwards describes the unit, not the street.
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i] == "PMB" || isSecondaryAt(tokens, i) {
+			parseSecondaryLine(c, tokens[i:])
+			tokens = tokens[:i]
+			break
+		}
+	}
+
+	street := tokens
+	if len(street) > 1 {
+		if dir, ok := directionals[street[len(street)-1]]; ok {
+			c.StreetPostdirection = dir
+			street = street[:len(street)-1]
+		}
+	}
+	if len(street) > 1 {
+		if suffix, ok := streetSuffixes[street[len(street)-1]]; ok {
+			c.StreetSuffix = suffix
+			street = street[:len(street)-1]
+		}
+	}
+	if len(street) > 1 {
+		if dir, ok := directionals[street[0]]; ok {
+			c.StreetPredirection = dir
+			street = street[1:]
+		}
+	}
+	if len(street) == 0 {
+		return ErrMissingStreetName
+	}
+	c.StreetName = strings.Join(street, " ")
+	return nil
+}
+
+// parseSecondaryLine records the secondary unit and private mailbox found in tokens, returning
+// whatever text it could not place.
+func parseSecondaryLine(c *USAddressComponents, tokens []string) string {
+	var rest []string
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok == "PMB" && i+1 < len(tokens):
+			c.PmbDesignator, c.PmbNumber = "PMB", tokens[i+1]
+			i++
+		case isSecondaryAt(tokens, i):
+			designator := secondaryDesignators[tok]
+			var number string
+			if secondaryNeedsRange(designator) {
+				number = tokens[i+1]
+				i++
+			}
+			if c.SecondaryDesignator == "" {
+				c.SecondaryDesignator, c.SecondaryNumber = designator, number
+			} else {
+				c.ExtraSecondary_designator, c.ExtraSecondary_number = designator, number
+			}
+		default:
+			rest = append(rest, tok)
+		}
+	}
+
+	// A bare unit number such as "4B" on address_line2 is written with the "#" designator.
+	if len(rest) == 1 && len(tokens) == 1 && containsDigit(rest[0]) && c.SecondaryDesignator == "" {
+		c.SecondaryDesignator, c.SecondaryNumber = "#", rest[0]
+		return ""
+	}
+	return strings.Join(rest, " ")
+}
+
+// isSecondaryAt reports whether tokens[i] starts a secondary unit. Designators that take a range,
+// such as APT or STE, must be followed by one; the others, such as REAR, must end the line.
+func isSecondaryAt(tokens []string, i int) bool {
+	designator, ok := secondaryDesignators[tokens[i]]
+	if !ok {
+		return false
+	}
+	if secondaryNeedsRange(designator) {
+		return i+1 < len(tokens)
+	}
+	return i == len(tokens)-1
+}
+
+func secondaryNeedsRange(designator string) bool {
+	return !secondaryWithoutRange[designator]
+}
+
+// poBoxPrefix returns how many tokens of a post office box prefix start tokens, or 0.
+func poBoxPrefix(tokens []string) int {
+	prefixes := [][]string{
+		{"POST", "OFFICE", "BOX"},
+		{"P", "O", "BOX"},
+		{"PO", "BOX"},
+		{"POB"},
+	}
+	for _, prefix := range prefixes {
+		if len(tokens) < len(prefix) {
+			continue
+		}
+		match := true
+		for i, p := range prefix {
+			if tokens[i] != p {
+				match = false
+				break
+			}
+		}
+		if match {
+			return len(prefix)
+		}
+	}
+	return 0
+}
+
+// addressTokens upper-cases s, strips the punctuation USPS drops, separates "#" from unit numbers
+// and splits the result on white space.
+func addressTokens(s string) []string {
+	s = strings.ToUpper(s)
+	s = strings.NewReplacer(".", "", ",", " ", ";", " ", "#", " # ").Replace(s)
+	return strings.Fields(s)
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func containsDigit(s string) bool {
+	return strings.IndexAny(s, "0123456789") >= 0
+}
+
+func joinNonEmpty(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// directionals maps spelled out and abbreviated directionals to their USPS abbreviation.
+var directionals = map[string]string{
+	"N": "N", "NORTH": "N",
+	"S": "S", "SOUTH": "S",
+	"E": "E", "EAST": "E",
+	"W": "W", "WEST": "W",
+	"NE": "NE", "NORTHEAST": "NE",
+	"NW": "NW", "NORTHWEST": "NW",
+	"SE": "SE", "SOUTHEAST": "SE",
+	"SW": "SW", "SOUTHWEST": "SW",
+}
+
+// secondaryDesignators maps secondary unit designators to their USPS abbreviation (Publication 28,
+// appendix C2).
+var secondaryDesignators = expandAbbreviations(map[string][]string{
+	"#":    {"#"},
+	"APT":  {"APARTMENT", "APT"},
+	"BSMT": {"BASEMENT", "BSMT"},
+	"BLDG": {"BUILDING", "BLDG"},
+	"DEPT": {"DEPARTMENT", "DEPT"},
+	"FL":   {"FLOOR", "FL"},
+	"FRNT": {"FRONT", "FRNT"},
+	"HNGR": {"HANGAR", "HNGR"},
+	"KEY":  {"KEY"},
+	"LBBY": {"LOBBY", "LBBY"},
+	"LOT":  {"LOT"},
+	"LOWR": {"LOWER", "LOWR"},
+	"OFC":  {"OFFICE", "OFC"},
+	"PH":   {"PENTHOUSE", "PH"},
+	"PIER": {"PIER"},
+	"REAR": {"REAR"},
+	"RM":   {"ROOM", "RM"},
+	"SIDE": {"SIDE"},
+	"SLIP": {"SLIP"},
+	"SPC":  {"SPACE", "SPC"},
+	"STOP": {"STOP"},
+	"STE":  {"SUITE", "STE"},
+	"TRLR": {"TRAILER", "TRLR"},
+	"UNIT": {"UNIT"},
+	"UPPR": {"UPPER", "UPPR"},
+})
+
+// secondaryWithoutRange lists the designators that are not followed by a unit number.
+var secondaryWithoutRange = map[string]bool{
+	"BSMT": true,
+	"FRNT": true,
+	"LBBY": true,
+	"LOWR": true,
+	"OFC":  true,
+	"PH":   true,
+	"REAR": true,
+	"SIDE": true,
+	"UPPR": true,
+}
+
+// streetSuffixes maps street suffixes and their common abbreviations to the USPS standard
+// abbreviation (Publication 28, appendix C1).
+var streetSuffixes = expandAbbreviations(map[string][]string{
+	"ALY":  {"ALLEE", "ALLEY", "ALLY", "ALY"},
+	"ANX":  {"ANEX", "ANNEX", "ANNX", "ANX"},
+	"ARC":  {"ARC", "ARCADE"},
+	"AVE":  {"AV", "AVE", "AVEN", "AVENU", "AVENUE", "AVN", "AVNUE"},
+	"BYU":  {"BAYOO", "BAYOU", "BYU"},
+	"BCH":  {"BCH", "BEACH"},
+	"BND":  {"BEND", "BND"},
+	"BLF":  {"BLF", "BLUF", "BLUFF"},
+	"BLFS": {"BLFS", "BLUFFS"},
+	"BTM":  {"BOT", "BOTTM", "BOTTOM", "BTM"},
+	"BLVD": {"BLVD", "BOUL", "BOULEVARD", "BOULV"},
+	"BR":   {"BR", "BRANCH", "BRNCH"},
+	"BRG":  {"BRDGE", "BRG", "BRIDGE"},
+	"BRK":  {"BRK", "BROOK"},
+	"BRKS": {"BRKS", "BROOKS"},
+	"BG":   {"BG", "BURG"},
+	"BGS":  {"BGS", "BURGS"},
+	"BYP":  {"BYP", "BYPA", "BYPAS", "BYPASS", "BYPS"},
+	"CP":   {"CAMP", "CMP", "CP"},
+	"CYN":  {"CANYN", "CANYON", "CNYN", "CYN"},
+	"CPE":  {"CAPE", "CPE"},
+	"CSWY": {"CAUSEWAY", "CAUSWA", "CSWY"},
+	"CTR":  {"CEN", "CENT", "CENTER", "CENTR", "CENTRE", "CNTER", "CNTR", "CTR"},
+	"CTRS": {"CENTERS", "CTRS"},
+	"CIR":  {"CIR", "CIRC", "CIRCL", "CIRCLE", "CRCL", "CRCLE"},
+	"CIRS": {"CIRCLES", "CIRS"},
+	"CLF":  {"CLF", "CLIFF"},
+	"CLFS": {"CLFS", "CLIFFS"},
+	"CLB":  {"CLB", "CLUB"},
+	"CMN":  {"CMN", "COMMON"},
+	"CMNS": {"CMNS", "COMMONS"},
+	"COR":  {"COR", "CORNER"},
+	"CORS": {"CORNERS", "CORS"},
+	"CRSE": {"COURSE", "CRSE"},
+	"CT":   {"COURT", "CT"},
+	"CTS":  {"COURTS", "CTS"},
+	"CV":   {"COVE", "CV"},
+	"CVS":  {"COVES", "CVS"},
+	"CRK":  {"CREEK", "CRK"},
+	"CRES": {"CRES", "CRESCENT", "CRSENT", "CRSNT"},
+	"CRST": {"CREST", "CRST"},
+	"XING": {"CROSSING", "CRSSNG", "XING"},
+	"XRD":  {"CROSSROAD", "XRD"},
+	"XRDS": {"CROSSROADS", "XRDS"},
+	"CURV": {"CURV", "CURVE"},
+	"DL":   {"DALE", "DL"},
+	"DM":   {"DAM", "DM"},
+	"DV":   {"DIV", "DIVIDE", "DV", "DVD"},
+	"DR":   {"DR", "DRIV", "DRIVE", "DRV"},
+	"DRS":  {"DRIVES", "DRS"},
+	"EST":  {"EST", "ESTATE"},
+	"ESTS": {"ESTATES", "ESTS"},
+	"EXPY": {"EXP", "EXPR", "EXPRESS", "EXPRESSWAY", "EXPW", "EXPY"},
+	"EXT":  {"EXT", "EXTENSION", "EXTN", "EXTNSN"},
+	"EXTS": {"EXTENSIONS", "EXTS"},
+	"FALL": {"FALL"},
+	"FLS":  {"FALLS", "FLS"},
+	"FRY":  {"FERRY", "FRRY", "FRY"},
+	"FLD":  {"FIELD", "FLD"},
+	"FLDS": {"FIELDS", "FLDS"},
+	"FLT":  {"FLAT", "FLT"},
+	"FLTS": {"FLATS", "FLTS"},
+	"FRD":  {"FORD", "FRD"},
+	"FRDS": {"FORDS", "FRDS"},
+	"FRST": {"FOREST", "FORESTS", "FRST"},
+	"FRG":  {"FORG", "FORGE", "FRG"},
+	"FRGS": {"FORGES", "FRGS"},
+	"FRK":  {"FORK", "FRK"},
+	"FRKS": {"FORKS", "FRKS"},
+	"FT":   {"FORT", "FRT", "FT"},
+	"FWY":  {"FREEWAY", "FREEWY", "FRWAY", "FRWY", "FWY"},
+	"GDN":  {"GARDEN", "GARDN", "GDN", "GRDEN", "GRDN"},
+	"GDNS": {"GARDENS", "GDNS", "GRDNS"},
+	"GTWY": {"GATEWAY", "GATEWY", "GATWAY", "GTWAY", "GTWY"},
+	"GLN":  {"GLEN", "GLN"},
+	"GLNS": {"GLENS", "GLNS"},
+	"GRN":  {"GREEN", "GRN"},
+	"GRNS": {"GREENS", "GRNS"},
+	"GRV":  {"GROV", "GROVE", "GRV"},
+	"GRVS": {"GROVES", "GRVS"},
+	"HBR":  {"HARB", "HARBOR", "HARBR", "HBR", "HRBOR"},
+	"HBRS": {"HARBORS", "HBRS"},
+	"HVN":  {"HAVEN", "HVN"},
+	"HTS":  {"HEIGHTS", "HT", "HTS"},
+	"HWY":  {"HIGHWAY", "HIGHWY", "HIWAY", "HIWY", "HWAY", "HWY"},
+	"HL":   {"HILL", "HL"},
+	"HLS":  {"HILLS", "HLS"},
+	"HOLW": {"HLLW", "HOLLOW", "HOLLOWS", "HOLW", "HOLWS"},
+	"INLT": {"INLET", "INLT"},
+	"IS":   {"IS", "ISLAND", "ISLND"},
+	"ISS":  {"ISLANDS", "ISLNDS", "ISS"},
+	"ISLE": {"ISLE", "ISLES"},
+	"JCT":  {"JCT", "JCTION", "JCTN", "JUNCTION", "JUNCTN", "JUNCTON"},
+	"JCTS": {"JCTNS", "JCTS", "JUNCTIONS"},
+	"KY":   {"KEY", "KY"},
+	"KYS":  {"KEYS", "KYS"},
+	"KNL":  {"KNL", "KNOL", "KNOLL"},
+	"KNLS": {"KNLS", "KNOLLS"},
+	"LK":   {"LAKE", "LK"},
+	"LKS":  {"LAKES", "LKS"},
+	"LAND": {"LAND"},
+	"LNDG": {"LANDING", "LNDG", "LNDNG"},
+	"LN":   {"LANE", "LN"},
+	"LGT":  {"LGT", "LIGHT"},
+	"LGTS": {"LGTS", "LIGHTS"},
+	"LF":   {"LF", "LOAF"},
+	"LCK":  {"LCK", "LOCK"},
+	"LCKS": {"LCKS", "LOCKS"},
+	"LDG":  {"LDG", "LDGE", "LODG", "LODGE"},
+	"LOOP": {"LOOP", "LOOPS"},
+	"MALL": {"MALL"},
+	"MNR":  {"MANOR", "MNR"},
+	"MNRS": {"MANORS", "MNRS"},
+	"MDW":  {"MDW", "MEADOW"},
+	"MDWS": {"MDWS", "MEADOWS", "MEDOWS"},
+	"MEWS": {"MEWS"},
+	"ML":   {"MILL", "ML"},
+	"MLS":  {"MILLS", "MLS"},
+	"MSN":  {"MISSION", "MISSN", "MSN", "MSSN"},
+	"MTWY": {"MOTORWAY", "MTWY"},
+	"MT":   {"MNT", "MOUNT", "MT"},
+	"MTN":  {"MNTAIN", "MNTN", "MOUNTAIN", "MOUNTIN", "MTIN", "MTN"},
+	"MTNS": {"MNTNS", "MOUNTAINS", "MTNS"},
+	"NCK":  {"NCK", "NECK"},
+	"ORCH": {"ORCH", "ORCHARD", "ORCHRD"},
+	"OVAL": {"OVAL", "OVL"},
+	"OPAS": {"OPAS", "OVERPASS"},
+	"PARK": {"PARK", "PARKS", "PRK"},
+	"PKWY": {"PARKWAY", "PARKWAYS", "PARKWY", "PKWAY", "PKWY", "PKWYS", "PKY"},
+	"PASS": {"PASS"},
+	"PSGE": {"PASSAGE", "PSGE"},
+	"PATH": {"PATH", "PATHS"},
+	"PIKE": {"PIKE", "PIKES"},
+	"PNE":  {"PINE", "PNE"},
+	"PNES": {"PINES", "PNES"},
+	"PL":   {"PL", "PLACE"},
+	"PLN":  {"PLAIN", "PLN"},
+	"PLNS": {"PLAINS", "PLNS"},
+	"PLZ":  {"PLAZA", "PLZ", "PLZA"},
+	"PT":   {"POINT", "PT"},
+	"PTS":  {"POINTS", "PTS"},
+	"PRT":  {"PORT", "PRT"},
+	"PRTS": {"PORTS", "PRTS"},
+	"PR":   {"PR", "PRAIRIE", "PRR"},
+	"RADL": {"RAD", "RADIAL", "RADIEL", "RADL"},
+	"RAMP": {"RAMP"},
+	"RNCH": {"RANCH", "RANCHES", "RNCH", "RNCHS"},
+	"RPD":  {"RAPID", "RPD"},
+	"RPDS": {"RAPIDS", "RPDS"},
+	"RST":  {"REST", "RST"},
+	"RDG":  {"RDG", "RDGE", "RIDGE"},
+	"RDGS": {"RDGS", "RIDGES"},
+	"RIV":  {"RIV", "RIVER", "RIVR", "RVR"},
+	"RD":   {"RD", "ROAD"},
+	"RDS":  {"RDS", "ROADS"},
+	"RTE":  {"ROUTE", "RTE"},
+	"ROW":  {"ROW"},
+	"RUE":  {"RUE"},
+	"RUN":  {"RUN"},
+	"SHL":  {"SHL", "SHOAL"},
+	"SHLS": {"SHLS", "SHOALS"},
+	"SHR":  {"SHOAR", "SHORE", "SHR"},
+	"SHRS": {"SHOARS", "SHORES", "SHRS"},
+	"SKWY": {"SKWY", "SKYWAY"},
+	"SPG":  {"SPG", "SPNG", "SPRING", "SPRNG"},
+	"SPGS": {"SPGS", "SPNGS", "SPRINGS", "SPRNGS"},
+	"SPUR": {"SPUR", "SPURS"},
+	"SQ":   {"SQ", "SQR", "SQRE", "SQU", "SQUARE"},
+	"SQS":  {"SQRS", "SQS", "SQUARES"},
+	"STA":  {"STA", "STATION", "STATN", "STN"},
+	"STRA": {"STRA", "STRAV", "STRAVEN", "STRAVENUE", "STRAVN", "STRVN", "STRVNUE"},
+	"STRM": {"STREAM", "STREME", "STRM"},
+	"ST":   {"ST", "STR", "STREET", "STRT"},
+	"STS":  {"STREETS", "STS"},
+	"SMT":  {"SMT", "SUMIT", "SUMITT", "SUMMIT"},
+	"TER":  {"TER", "TERR", "TERRACE"},
+	"TRWY": {"THROUGHWAY", "TRWY"},
+	"TRCE": {"TRACE", "TRACES", "TRCE"},
+	"TRAK": {"TRACK", "TRACKS", "TRAK", "TRK", "TRKS"},
+	"TRFY": {"TRAFFICWAY", "TRFY"},
+	"TRL":  {"TRAIL", "TRAILS", "TRL", "TRLS"},
+	"TRLR": {"TRAILER", "TRLR", "TRLRS"},
+	"TUNL": {"TUNEL", "TUNL", "TUNLS", "TUNNEL", "TUNNELS", "TUNNL"},
+	"TPKE": {"TPKE", "TRNPK", "TURNPIKE", "TURNPK"},
+	"UPAS": {"UNDERPASS", "UPAS"},
+	"UN":   {"UN", "UNION"},
+	"UNS":  {"UNIONS", "UNS"},
+	"VLY":  {"VALLEY", "VALLY", "VLLY", "VLY"},
+	"VLYS": {"VALLEYS", "VLYS"},
+	"VIA":  {"VDCT", "VIA", "VIADCT", "VIADUCT"},
+	"VW":   {"VIEW", "VW"},
+	"VWS":  {"VIEWS", "VWS"},
+	"VLG":  {"VILL", "VILLAG", "VILLAGE", "VILLG", "VILLIAGE", "VLG"},
+	"VLGS": {"VILLAGES", "VLGS"},
+	"VL":   {"VILLE", "VL"},
+	"VIS":  {"VIS", "VIST", "VISTA", "VST", "VSTA"},
+	"WALK": {"WALK", "WALKS"},
+	"WALL": {"WALL"},
+	"WAY":  {"WAY", "WY"},
+	"WAYS": {"WAYS"},
+	"WL":   {"WELL", "WL"},
+	"WLS":  {"WELLS", "WLS"},
+})
+
+func expandAbbreviations(table map[string][]string) map[string]string {
+	lookup := make(map[string]string)
+	for abbreviation, variants := range table {
+		for _, v := range variants {
+			lookup[v] = abbreviation
+		}
+	}
+	return lookup
+}
+
+// usStateNames maps USPS state, territory and military state codes to their names.
+var usStateNames = map[string]string{
+	"AL": "ALABAMA",
+	"AK": "ALASKA",
+	"AZ": "ARIZONA",
+	"AR": "ARKANSAS",
+	"CA": "CALIFORNIA",
+	"CO": "COLORADO",
+	"CT": "CONNECTICUT",
+	"DE": "DELAWARE",
+	"DC": "DISTRICT OF COLUMBIA",
+	"FL": "FLORIDA",
+	"GA": "GEORGIA",
+	"HI": "HAWAII",
+	"ID": "IDAHO",
+	"IL": "ILLINOIS",
+	"IN": "INDIANA",
+	"IA": "IOWA",
+	"KS": "KANSAS",
+	"KY": "KENTUCKY",
+	"LA": "LOUISIANA",
+	"ME": "MAINE",
+	"MD": "MARYLAND",
+	"MA": "MASSACHUSETTS",
+	"MI": "MICHIGAN",
+	"MN": "MINNESOTA",
+	"MS": "MISSISSIPPI",
+	"MO": "MISSOURI",
+	"MT": "MONTANA",
+	"NE": "NEBRASKA",
+	"NV": "NEVADA",
+	"NH": "NEW HAMPSHIRE",
+	"NJ": "NEW JERSEY",
+	"NM": "NEW MEXICO",
+	"NY": "NEW YORK",
+	"NC": "NORTH CAROLINA",
+	"ND": "NORTH DAKOTA",
+	"OH": "OHIO",
+	"OK": "OKLAHOMA",
+	"OR": "OREGON",
+	"PA": "PENNSYLVANIA",
+	"RI": "RHODE ISLAND",
+	"SC": "SOUTH CAROLINA",
+	"SD": "SOUTH DAKOTA",
+	"TN": "TENNESSEE",
+	"TX": "TEXAS",
+	"UT": "UTAH",
+	"VT": "VERMONT",
+	"VA": "VIRGINIA",
+	"WA": "WASHINGTON",
+	"WV": "WEST VIRGINIA",
+	"WI": "WISCONSIN",
+	"WY": "WYOMING",
+	"AS": "AMERICAN SAMOA",
+	"FM": "FEDERATED STATES OF MICRONESIA",
+	"GU": "GUAM",
+	"MH": "MARSHALL ISLANDS",
+	"MP": "NORTHERN MARIANA ISLANDS",
+	"PW": "PALAU",
+	"PR": "PUERTO RICO",
+	"VI": "VIRGIN ISLANDS",
+	"AA": "ARMED FORCES AMERICAS",
+	"AE": "ARMED FORCES EUROPE",
+	"AP": "ARMED FORCES PACIFIC",
+}
+
+// usStateCodes is the inverse of usStateNames.
+var usStateCodes = func() map[string]string {
+	codes := make(map[string]string, len(usStateNames))
+	for code, name := range usStateNames {
+		codes[name] = code
+	}
+	return codes
+}()
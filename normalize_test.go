@@ -0,0 +1,119 @@
+package lob
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeUSAddress(t *testing.T) {
+	tests := []struct {
+		address  Address
+		line1    string
+		line2    string
+		lastLine string
+	}{
+		{
+			address: Address{
+				AddressLine1: "1005 West Burnside Street",
+				AddressCity:  nullString("Portland"),
+				AddressState: nullString("Oregon"),
+				AddressZip:   nullString("97209"),
+			},
+			line1:    "1005 W BURNSIDE ST",
+			lastLine: "PORTLAND OR 97209",
+		},
+		{
+			address: Address{
+				AddressLine1: "185 Berry St., Suite 6100",
+				AddressCity:  nullString("San Francisco"),
+				AddressState: nullString("ca"),
+				AddressZip:   nullString("941071741"),
+			},
+			line1:    "185 BERRY ST STE 6100",
+			lastLine: "SAN FRANCISCO CA 94107-1741",
+		},
+		{
+			address: Address{
+				AddressLine1: "123 North St",
+				AddressLine2: nullString("Apartment 4B"),
+				AddressZip:   nullString("02134"),
+			},
+			line1:    "123 NORTH ST APT 4B",
+			lastLine: "02134",
+		},
+		{
+			address: Address{
+				AddressLine1: "500 Main Avenue NW",
+				AddressLine2: nullString("#12"),
+				AddressCity:  nullString("Washington"),
+				AddressState: nullString("District of Columbia"),
+				AddressZip:   nullString("20001-1234"),
+			},
+			line1:    "500 MAIN AVE NW # 12",
+			lastLine: "WASHINGTON DC 20001-1234",
+		},
+		{
+			address: Address{
+				AddressLine1: "P.O. Box 42",
+				AddressCity:  nullString("Davis"),
+				AddressState: nullString("CA"),
+			},
+			line1:    "PO BOX 42",
+			lastLine: "DAVIS CA",
+		},
+		{
+			address: Address{
+				AddressLine1: "77 Sunset Boulevard PMB 9",
+				AddressLine2: nullString("c/o Accounts Payable"),
+				AddressZip:   nullString("90210"),
+			},
+			line1:    "77 SUNSET BLVD",
+			line2:    "PMB 9 C/O ACCOUNTS PAYABLE",
+			lastLine: "90210",
+		},
+	}
+
+	for _, test := range tests {
+		normalized, err := NormalizeUSAddress(&test.address)
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", test.address.AddressLine1, err)
+			continue
+		}
+		if normalized.AddressLine1 != test.line1 {
+			t.Errorf("%q: expected line 1 %q, got %q", test.address.AddressLine1, test.line1, normalized.AddressLine1)
+		}
+		var line2 string
+		if normalized.AddressLine2 != nil {
+			line2 = *normalized.AddressLine2
+		}
+		if line2 != test.line2 {
+			t.Errorf("%q: expected line 2 %q, got %q", test.address.AddressLine1, test.line2, line2)
+		}
+		components, err := ParseUSAddress(&test.address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if components.LastLine() != test.lastLine {
+			t.Errorf("%q: expected last line %q, got %q", test.address.AddressLine1, test.lastLine, components.LastLine())
+		}
+	}
+}
+
+func TestNormalizeUSAddressErrors(t *testing.T) {
+	tests := []struct {
+		address Address
+		err     error
+	}{
+		{Address{AddressLine1: "Main St", AddressZip: nullString("97209")}, ErrMissingPrimaryNumber},
+		{Address{AddressLine1: "1 Main St", AddressZip: nullString("9720")}, ErrInvalidZip},
+		{Address{AddressLine1: "1 Main St", AddressCity: nullString("Portland"), AddressState: nullString("Oregone")}, ErrUnknownState},
+		{Address{AddressLine1: "1 Main St", AddressCity: nullString("Portland")}, ErrIncompleteLastLine},
+		{Address{AddressLine1: "1 Main St", AddressCountry: nullString("CA"), AddressZip: nullString("97209")}, ErrNotUSAddress},
+	}
+
+	for _, test := range tests {
+		if _, err := NormalizeUSAddress(&test.address); !errors.Is(err, test.err) {
+			t.Errorf("%+v: expected error %v, got %v", test.address, test.err, err)
+		}
+	}
+}
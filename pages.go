@@ -0,0 +1,53 @@
+package lob
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxListCount is the most objects Lob lists at once.
+const maxListCount = 100
+
+// Pager is implemented by Lobs that can follow the NextURL and PreviousURL of a list to the pages
+// after and before it. The client and FakeLob implement it.
+type Pager interface {
+	ListChecksPage(pageURL string) (*ListChecksResponse, error)
+	ListAddressesPage(pageURL string) (*ListAddressesResponse, error)
+	ListBankAccountsPage(pageURL string) (*ListBankAccountsResponse, error)
+}
+
+// ListChecksPage retrieves the page of checks at a NextURL or PreviousURL.
+func (l *lob) ListChecksPage(pageURL string) (*ListChecksResponse, error) {
+	resp := new(ListChecksResponse)
+	if err := l.getPage(pageURL, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListAddressesPage retrieves the page of addresses at a NextURL or PreviousURL.
+func (l *lob) ListAddressesPage(pageURL string) (*ListAddressesResponse, error) {
+	resp := new(ListAddressesResponse)
+	if err := l.getPage(pageURL, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListBankAccountsPage retrieves the page of bank accounts at a NextURL or PreviousURL.
+func (l *lob) ListBankAccountsPage(pageURL string) (*ListBankAccountsResponse, error) {
+	resp := new(ListBankAccountsResponse)
+	if err := l.getPage(pageURL, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// getPage performs a GET request for a page URL Lob returned. Page URLs are absolute, so only
+// those under the client's BaseAPI are followed, to keep the API key from being sent elsewhere.
+func (l *lob) getPage(pageURL string, returnValue interface{}) error {
+	if !strings.HasPrefix(pageURL, l.BaseAPI) {
+		return fmt.Errorf("page URL %s is not under %s", redactURL(pageURL), l.BaseAPI)
+	}
+	return l.do("GET", strings.TrimPrefix(pageURL, l.BaseAPI), nil, returnValue)
+}
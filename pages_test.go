@@ -0,0 +1,33 @@
+package lob
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListPage(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		w.Write([]byte(`{"data": [{"id": "adr_1"}], "object": "list", "previous_url": "prev", "count": 1}`))
+	}))
+	defer server.Close()
+
+	l := NewLob(server.URL+"/v1/", "test_key", testUserAgent)
+	list, err := l.ListAddressesPage(server.URL + "/v1/addresses?after=adr_2&limit=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requested != "/v1/addresses?after=adr_2&limit=1" {
+		t.Errorf("Expected the page URL to be requested, got %s", requested)
+	}
+	if list.Count != 1 || list.Data[0].ID != "adr_1" || list.PreviousURL != "prev" {
+		t.Errorf("Expected the page, got %+v", list)
+	}
+
+	requested = ""
+	if _, err := l.ListChecksPage("https://example.com/v1/checks?after=chk_1"); err == nil || requested != "" {
+		t.Errorf("Expected a page URL on another host not to be requested, got %v", err)
+	}
+}
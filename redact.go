@@ -0,0 +1,133 @@
+package lob
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// APIError is returned when Lob responds with a status other than 200. Its URL and body are
+// redacted, so it can be logged without leaking API keys, bank account numbers or addresses.
+type APIError struct {
+	StatusCode int
+	URL        string
+	Body       []byte
+
+	// err is the error the response stands for, if any, such as ErrCheckNotCancellable.
+	err error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Non-200 status code %d returned from %s with body %s", e.StatusCode, e.URL, e.Body)
+}
+
+// Non200Error was returned for responses with a status other than 200.
+//
+// Deprecated: The client returns an *APIError, with the status and body, instead. Use errors.As
+// to inspect it.
+var Non200Error = errors.New("Non-200 Status code returned")
+
+// ErrNotFound matches, with errors.Is, the errors returned for objects Lob does not have.
+var ErrNotFound = errors.New("not found")
+
+// Is reports whether a 404 is ErrNotFound.
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// Unwrap returns the error the response stands for, such as ErrCheckNotCancellable for a 422
+// cancelling a check, or nil.
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// redacted replaces values that are removed entirely.
+const redacted = "[redacted]"
+
+// redactedFields are response fields holding secrets or personal information. Account numbers
+// keep their last four digits so that they can still be told apart.
+var redactedFields = map[string]func(string) string{
+	"account_number": maskAccountNumber,
+	"routing_number": maskAccountNumber,
+	"signatory":      redactValue,
+	"name":           redactValue,
+	"email":          redactValue,
+	"phone":          redactValue,
+	"address_line1":  redactValue,
+	"address_line2":  redactValue,
+	"primary_line":   redactValue,
+	"secondary_line": redactValue,
+	"recipient":      redactValue,
+}
+
+// digitsPattern matches runs of digits long enough to be account numbers.
+var digitsPattern = regexp.MustCompile(`\b\d{6,}\b`)
+
+func redactValue(string) string {
+	return redacted
+}
+
+// maskAccountNumber masks all but the last four characters of an account or routing number.
+func maskAccountNumber(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
+
+// redactKey masks an API key, keeping its environment prefix.
+func redactKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	return string(KeyEnvironment(key)) + "_" + redacted
+}
+
+// redactURL removes any credentials from a URL.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return redacted
+	}
+	return u.Redacted()
+}
+
+// redactBody redacts the sensitive fields of a JSON response body. Bodies that are not JSON
+// have long runs of digits masked instead.
+func redactBody(data []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return []byte(digitsPattern.ReplaceAllStringFunc(string(data), maskAccountNumber))
+	}
+	redacted, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if s, ok := field.(string); ok {
+				if redact, ok := redactedFields[k]; ok {
+					v[k] = redact(s)
+					continue
+				}
+			}
+			v[k] = redactJSON(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	case string:
+		return digitsPattern.ReplaceAllStringFunc(v, maskAccountNumber)
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+package lob
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := `{"error": {"message": "account 123456789 is closed", "status_code": 422},
+		"id": "bank_8cad8df5354d33f",
+		"account_number": "123456789",
+		"routing_number": "322271627",
+		"signatory": "John Doe",
+		"to": {"name": "Harry Zhang", "address_line1": "185 Berry St", "address_zip": "94107"}}`
+	redacted := string(redactBody([]byte(body)))
+
+	for _, secret := range []string{"123456789", "322271627", "John Doe", "Harry Zhang", "185 Berry St"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("Expected %q to be redacted from %s", secret, redacted)
+		}
+	}
+	for _, kept := range []string{"*****6789", "*****1627", "bank_8cad8df5354d33f", "94107", "is closed"} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("Expected %q to be kept in %s", kept, redacted)
+		}
+	}
+
+	if redacted := string(redactBody([]byte("bad gateway for 123456789"))); redacted != "bad gateway for *****6789" {
+		t.Errorf("Expected digits to be masked in a non-JSON body, got %s", redacted)
+	}
+}
+
+func TestAPIErrorRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error": {"message": "invalid", "status_code": 422}, "account_number": "123456789"}`))
+	}))
+	defer server.Close()
+	l := NewLob(server.URL+"/", "test_0dc8d51e0acffcb1880e0f19c79b2f5b0cc", testUserAgent)
+
+	resp, err := l.GetBankAccount("bank_123")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 422 {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if strings.Contains(err.Error(), "123456789") {
+		t.Errorf("Expected the account number to be redacted, got %s", err)
+	}
+	if resp == nil || resp.Error == nil || resp.Error.StatusCode != 422 || strings.Contains(resp.AccountNumber, "123456789") {
+		t.Errorf("Expected only Lob's error and redacted fields on the bank account, got %#v", resp)
+	}
+
+	for _, s := range []string{fmt.Sprint(l), fmt.Sprintf("%+v", l), fmt.Sprintf("%#v", l)} {
+		if strings.Contains(s, "0dc8d51e") {
+			t.Errorf("Expected the API key to be redacted, got %s", s)
+		}
+	}
+}
+
+func TestBankAccountStringMasked(t *testing.T) {
+	account := BankAccount{ID: "bank_123", AccountNumber: "123456789", RoutingNumber: "322271627"}
+	req := CreateBankAccountRequest{AccountNumber: "123456789", RoutingNumber: "322271627", Signatory: "John Doe"}
+
+	for _, s := range []string{
+		fmt.Sprint(account), fmt.Sprintf("%+v", &account), fmt.Sprintf("%#v", account),
+		fmt.Sprint(req), fmt.Sprintf("%v", &req), fmt.Sprintf("%#v", req),
+		fmt.Sprint(ListBankAccountsResponse{Data: []BankAccount{account}}),
+	} {
+		if strings.Contains(s, "123456789") || strings.Contains(s, "322271627") {
+			t.Errorf("Expected numbers to be masked, got %s", s)
+		}
+		if !strings.Contains(s, "*****6789") {
+			t.Errorf("Expected the last four digits to be kept, got %s", s)
+		}
+	}
+	if s := fmt.Sprintf("%#v", account); !strings.HasPrefix(s, "lob.BankAccount{") {
+		t.Errorf("Expected a Go syntax representation, got %s", s)
+	}
+	if account.AccountNumber != "123456789" {
+		t.Error("Expected formatting to leave the account number unchanged")
+	}
+}
@@ -1,5 +1,10 @@
 package lob
 
+import (
+	"sort"
+	"strings"
+)
+
 // NamedObjectList is used to return the list of countries and states.
 type NamedObjectList struct {
 	Object string        `json:"object"`
@@ -31,3 +36,53 @@ func (l *lob) GetCountries() (*NamedObjectList, error) {
 	}
 	return resp, nil
 }
+
+// USStates returns the states, territories and military states Lob accepts in US addresses, in
+// the form GetStates returns them. Fakes and simulators use it as their fixture.
+func USStates() *NamedObjectList {
+	list := &NamedObjectList{Object: "list"}
+	for code, name := range usStateNames {
+		list.Data = append(list.Data, NamedObject{
+			ID:        code,
+			Name:      titleCase(name),
+			ShortName: code,
+			Object:    "state",
+		})
+	}
+	sortNamedObjects(list.Data)
+	return list
+}
+
+// Countries returns the countries Lob accepts in addresses, by ISO 3166-1 code, in the form
+// GetCountries returns them. Fakes and simulators use it as their fixture.
+func Countries() *NamedObjectList {
+	list := &NamedObjectList{Object: "list"}
+	for code, name := range countryNames {
+		list.Data = append(list.Data, NamedObject{
+			ID:        code,
+			Name:      name,
+			ShortName: code,
+			Object:    "country",
+		})
+	}
+	sortNamedObjects(list.Data)
+	return list
+}
+
+func sortNamedObjects(objects []NamedObject) {
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Name < objects[j].Name
+	})
+}
+
+// titleCase capitalizes the words of an upper case name, e.g. "DISTRICT OF COLUMBIA" becomes
+// "District of Columbia".
+func titleCase(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	for i, w := range words {
+		if w != "of" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,61 @@
+package lob
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Response describes the HTTP response to a call, for the details Lob support asks for and for
+// fields this package does not decode.
+type Response struct {
+	StatusCode int
+	// RequestID is Lob's identifier for the request.
+	RequestID string
+	RateLimit RateLimit
+	Header    http.Header
+	// Body is the response body as Lob sent it.
+	Body json.RawMessage
+}
+
+// RateLimit is the state of the account's rate limit after a call. Fields are zero when Lob did
+// not send them.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Rate limit headers sent by Lob. The reset header is a Unix time in seconds.
+const (
+	rateLimitLimitHeader     = "X-Rate-Limit-Limit"
+	rateLimitRemainingHeader = "X-Rate-Limit-Remaining"
+	rateLimitResetHeader     = "X-Rate-Limit-Reset"
+)
+
+// WithResponse returns a copy of the client that stores the response to each call in resp. resp
+// is reset whenever the client starts a request, and left zero if no response is received;
+// calls that fail validation make no request and leave it untouched. Since every call
+// overwrites resp, the copy should be used for one call at a time.
+func (l *lob) WithResponse(resp *Response) *lob {
+	c := *l
+	c.response = resp
+	return &c
+}
+
+// newResponse collects the metadata of an HTTP response.
+func newResponse(resp *http.Response, body []byte) Response {
+	r := Response{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(requestIDHeader),
+		Header:     resp.Header,
+		Body:       json.RawMessage(body),
+	}
+	r.RateLimit.Limit, _ = strconv.Atoi(resp.Header.Get(rateLimitLimitHeader))
+	r.RateLimit.Remaining, _ = strconv.Atoi(resp.Header.Get(rateLimitRemainingHeader))
+	if reset, err := strconv.ParseInt(resp.Header.Get(rateLimitResetHeader), 10, 64); err == nil {
+		r.RateLimit.Reset = time.Unix(reset, 0)
+	}
+	return r
+}
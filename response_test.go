@@ -0,0 +1,58 @@
+package lob
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_123")
+		w.Header().Set("X-Rate-Limit-Limit", "150")
+		w.Header().Set("X-Rate-Limit-Remaining", "149")
+		w.Header().Set("X-Rate-Limit-Reset", "1559390400")
+		if r.URL.Path == "/checks/chk_missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "check not found", "status_code": 404}}`))
+			return
+		}
+		w.Write([]byte(`{"id": "chk_123", "future_field": {"nested": true}}`))
+	}))
+	defer server.Close()
+
+	var resp Response
+	l := NewLob(server.URL+"/", "test_key", testUserAgent)
+	if _, err := l.WithResponse(&resp).GetCheck("chk_123"); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != 200 || resp.RequestID != "req_123" {
+		t.Errorf("Expected the status and request ID, got %+v", resp)
+	}
+	expected := RateLimit{Limit: 150, Remaining: 149, Reset: time.Unix(1559390400, 0)}
+	if resp.RateLimit != expected {
+		t.Errorf("Expected rate limit %+v, got %+v", expected, resp.RateLimit)
+	}
+	if string(resp.Body) != `{"id": "chk_123", "future_field": {"nested": true}}` {
+		t.Errorf("Expected the raw body, got %s", resp.Body)
+	}
+	if resp.Header.Get("X-Rate-Limit-Limit") != "150" {
+		t.Errorf("Expected the response headers, got %v", resp.Header)
+	}
+
+	if _, err := l.WithResponse(&resp).GetCheck("chk_missing"); err == nil {
+		t.Fatal("Expected an error for a missing check")
+	}
+	if resp.StatusCode != 404 || resp.RequestID != "req_123" {
+		t.Errorf("Expected the response to a failed call, got %+v", resp)
+	}
+
+	if _, err := l.WithAccount("unknown").WithResponse(&resp).GetCheck("chk_123"); err == nil {
+		t.Fatal("Expected an error for an unknown account")
+	}
+	if resp.StatusCode != 0 || resp.Body != nil {
+		t.Errorf("Expected the response to be reset when no request is sent, got %+v", resp)
+	}
+}
@@ -0,0 +1,26 @@
+package lob
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	logger.Log(LevelDebug, "lob request", Field{Key: "status", Value: 200})
+	if buf.Len() != 0 {
+		t.Errorf("Expected debug entries to be filtered, got %s", buf.String())
+	}
+
+	logger.Log(LevelWarn, "lob request", Field{Key: "method", Value: "GET"}, Field{Key: "status", Value: 404})
+	line := buf.String()
+	for _, s := range []string{"level=WARN", `msg="lob request"`, "method=GET", "status=404"} {
+		if !strings.Contains(line, s) {
+			t.Errorf("Expected %s in %s", s, line)
+		}
+	}
+}
@@ -1,223 +1,954 @@
 package lob
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
-
-	"github.com/pborman/uuid"
 )
 
-var Non200Error = errors.New("Non-200 Status code returned")
-
-type fakeLob struct {
+// DefaultCancellationWindow is how long the fake lets unscheduled checks be cancelled.
+const DefaultCancellationWindow = 24 * time.Hour
+
+// FakeLob is an in-memory Lob for tests. It stores what it is sent, validates requests like the
+// client does and is safe for concurrent use. Its exported fields should be set before it is
+// used. Failed calls return what the client returns: checks, addresses and bank accounts holding
+// only the error, with Lob's message when Lob sent one, and nil for lists, verifications, states
+// and countries.
+type FakeLob struct {
+	// Now returns the fake's current time. Tests can replace it to move checks past their send
+	// date.
+	Now func() time.Time
+	// CancellationWindow is how long after creation an unscheduled check can be cancelled.
+	CancellationWindow time.Duration
+	// Faults, if set, injects failures into calls to the fake.
+	Faults *Faults
+
+	mu           sync.Mutex
+	created      map[string]int
+	sequence     int
+	checkNumber  int
 	checks       map[string]*Check
 	addresses    map[string]*Address
-	objectLists  map[string]*NamedObjectList
 	bankAccounts map[string]*BankAccount
 }
 
-func NewFakeLob() *fakeLob {
-	return &fakeLob{
-		checks:       make(map[string]*Check),
-		addresses:    make(map[string]*Address),
-		objectLists:  make(map[string]*NamedObjectList),
-		bankAccounts: make(map[string]*BankAccount),
+// NewFakeLob returns a FakeLob with nothing stored.
+func NewFakeLob() *FakeLob {
+	return &FakeLob{
+		Now:                time.Now,
+		CancellationWindow: DefaultCancellationWindow,
+		created:            make(map[string]int),
+		checkNumber:        9999,
+		checks:             make(map[string]*Check),
+		addresses:          make(map[string]*Address),
+		bankAccounts:       make(map[string]*BankAccount),
 	}
 }
 
-func (t *fakeLob) CreateCheck(request *CreateCheckRequest) (*Check, error) {
+// Advance moves the fake's time forward by d, e.g. to move checks past their send date. Unlike
+// setting Now, it is safe while the fake is in use.
+func (t *FakeLob) Advance(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.Now
+	t.Now = func() time.Time { return now().Add(d) }
+}
+
+// call makes the named call unless a fault fails it first, and loses its response to a timeout or
+// malformed body if a fault says so.
+func (t *FakeLob) call(endpoint string, f func() error) error {
+	fault := t.Faults.Next(endpoint)
+	time.Sleep(fault.Latency)
+	if fault.StatusCode != 0 {
+		return fault.apiError(endpoint)
+	}
+	t.mu.Lock()
+	err := f()
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return fault.lostResponseError(endpoint)
+}
+
+func (t *FakeLob) CreateCheck(request *CreateCheckRequest) (*Check, error) {
+	var check *Check
+	err := t.call("CreateCheck", func() (err error) {
+		check, err = t.createCheck(request)
+		return err
+	})
+	if err != nil && !isValidationError(err) {
+		check = &Check{Error: lobError(err)}
+	}
+	return check, err
+}
+
+func (t *FakeLob) createCheck(request *CreateCheckRequest) (*Check, error) {
+	now := t.Now()
+	if err := request.validate(now); err != nil {
+		return &Check{Error: validationError(err)}, err
+	}
 
 	bankAccount, ok := t.bankAccounts[request.BankAccountID]
 	if !ok {
-		return nil, errors.New("bank account not found")
+		return nil, newFakeError("checks", http.StatusUnprocessableEntity, "bank account "+request.BankAccountID+" not found")
 	}
 
-	address, ok := t.addresses[request.ToAddressID]
-	if !ok {
-		return nil, errors.New("address not found")
+	address, err := t.resolveAddress(request.To)
+	if err != nil {
+		return nil, err
 	}
+	var from *Address
+	if !request.From.IsZero() {
+		if from, err = t.resolveAddress(request.From); err != nil {
+			return nil, err
+		}
+	}
+	sendDate := now.Add(t.CancellationWindow)
+	if request.SendDate != nil {
+		sendDate = *request.SendDate
+	}
+	var checkNumber int
+	if request.CheckNumber != nil {
+		// validate has checked that it is a positive integer.
+		checkNumber, _ = strconv.Atoi(*request.CheckNumber)
+	} else {
+		checkNumber = t.nextCheckNumber()
+	}
+	mailType := MailTypeUspsFirstClass
+	if request.MailType != nil {
+		mailType = *request.MailType
+	}
+	id := "chk_" + newFakeID()
 	check := &Check{
-		ID:                   uuid.New(),
+		ID:                   id,
 		Amount:               request.Amount,
+		Attachment:           request.Attachment,
 		BankAccount:          bankAccount,
-		CheckNumber:          rand.Int(),
-		ExpectedDeliveryDate: time.Now().Add(3 * 24 * time.Hour).Format("1/2/2006"),
-		SendDate:             time.Now().Add(1 * 24 * time.Hour),
+		Carrier:              "USPS",
+		CheckBottom:          request.CheckBottom,
+		CheckNumber:          checkNumber,
+		Data:                 request.Data,
+		MergeVariables:       request.MergeVariables,
+		DateCreated:          now,
+		DateModified:         now,
+		ExpectedDeliveryDate: DateOf(sendDate).AddDays(2),
+		SendDate:             Timestamp{sendDate},
+		From:                 from,
+		MailType:             &mailType,
+		Message:              request.Message,
+		Object:               "check",
 		To:                   address,
+		URL:                  "https://lob-assets.com/checks/" + id + ".pdf",
+	}
+	if request.Description != nil {
+		check.Description = *request.Description
+	}
+	if request.Memo != nil {
+		check.Memo = *request.Memo
 	}
-	t.checks[check.ID] = check
-	return check, nil
+	if request.Logo != nil && request.Logo.URL != "" {
+		check.Logo = &request.Logo.URL
+	}
+	check.fromVersion(APIVersion)
+	t.store(check.ID)
+	t.checks[check.ID] = copyCheck(check)
+	return copyCheck(check), nil
+}
+
+// resolveAddress looks up a stored address or, like Lob, stores an inline one.
+func (t *FakeLob) resolveAddress(ref AddressRef) (*Address, error) {
+	if ref.ID == "" && ref.Address != nil {
+		inline := *ref.Address
+		inline.ID = ""
+		return t.createAddress(&inline)
+	}
+	address, ok := t.addresses[ref.ID]
+	if !ok || isDeleted(address) {
+		return nil, newFakeError("checks", http.StatusUnprocessableEntity, "address "+ref.ID+" not found")
+	}
+	return address, nil
 }
 
-func (t *fakeLob) GetCheck(id string) (*Check, error) {
+func (t *FakeLob) GetCheck(id string) (*Check, error) {
+	var check *Check
+	err := t.call("GetCheck", func() (err error) {
+		check, err = t.getCheck(id)
+		return err
+	})
+	if err != nil {
+		check = &Check{Error: lobError(err)}
+	}
+	return check, err
+}
+
+func (t *FakeLob) getCheck(id string) (*Check, error) {
 	check, ok := t.checks[id]
 	if !ok {
-		return nil, errors.New("no check found")
+		return nil, newFakeError("checks/"+id, http.StatusNotFound, "check not found")
 	}
-	return check, nil
+	return copyCheck(check), nil
 }
 
-func (t *fakeLob) CancelCheck(id string) (*CancelCheckResponse, error) {
-	delete(t.checks, id)
+func (t *FakeLob) CancelCheck(id string) (*CancelCheckResponse, error) {
+	var resp *CancelCheckResponse
+	err := t.call("CancelCheck", func() (err error) {
+		resp, err = t.cancelCheck(id)
+		return err
+	})
+	if err != nil {
+		resp = new(CancelCheckResponse)
+	}
+	return resp, cancelError(err)
+}
+
+// cancelCheck cancels a check that has not yet been sent. Like Lob, it marks the check as deleted,
+// so it can still be retrieved but is no longer listed.
+func (t *FakeLob) cancelCheck(id string) (*CancelCheckResponse, error) {
+	check, ok := t.checks[id]
+	if !ok {
+		return nil, newFakeError("checks/"+id, http.StatusNotFound, "check not found")
+	}
+	now := t.Now()
+	if !check.Cancellable(now) {
+		return nil, newFakeError("checks/"+id, http.StatusUnprocessableEntity, ErrCheckNotCancellable.Error())
+	}
+	check.Deleted = true
+	check.DateModified = now
 	return &CancelCheckResponse{
 		ID:      id,
 		Deleted: true,
 	}, nil
 }
 
-func (t *fakeLob) ListChecks(count int) (*ListChecksResponse, error) {
-	if count <= 0 {
-		count = 10
+func (t *FakeLob) ListChecks(count int) (*ListChecksResponse, error) {
+	var resp *ListChecksResponse
+	err := t.call("ListChecks", func() (err error) {
+		resp, err = t.listChecks(listQuery{limit: count})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return resp, err
+}
 
-	resp := new(ListChecksResponse)
-
-	data := make([]Check, len(t.checks))
+func (t *FakeLob) ListChecksPage(pageURL string) (*ListChecksResponse, error) {
+	var resp *ListChecksResponse
+	err := t.call("ListChecks", func() error {
+		q, err := parsePageURL("checks", pageURL)
+		if err != nil {
+			return err
+		}
+		resp, err = t.listChecks(q)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, err
+}
 
-	for _, check := range t.checks {
-		data = append(data, *check)
+func (t *FakeLob) listChecks(q listQuery) (*ListChecksResponse, error) {
+	objects := make([]listed, 0, len(t.checks))
+	for id, check := range t.checks {
+		if !check.Deleted {
+			objects = append(objects, listed{id: id, created: check.DateCreated})
+		}
+	}
+	ids, nextURL, previousURL, err := t.page("checks", objects, q)
+	if err != nil {
+		return nil, err
 	}
 
-	resp.Data = data
-	resp.Count = count
+	resp := &ListChecksResponse{
+		Data:        make([]Check, 0, len(ids)),
+		Object:      "list",
+		NextURL:     nextURL,
+		PreviousURL: previousURL,
+	}
+	for _, id := range ids {
+		resp.Data = append(resp.Data, *copyCheck(t.checks[id]))
+	}
+	resp.Count = len(resp.Data)
 	return resp, nil
 }
 
 // Addresses
 
-func (t *fakeLob) CreateAddress(address *Address) (*Address, error) {
-	var message string
-	var status int
-	if address.Name != nil && len(*address.Name) > 40 {
-		message = "name length must be less than or equal to 40 characters long"
-		status = 422
-		address.Error = &Error{
-			Message:    message,
-			StatusCode: status,
-		}
-		return address, Non200Error
+func (t *FakeLob) CreateAddress(address *Address) (*Address, error) {
+	var created *Address
+	err := t.call("CreateAddress", func() (err error) {
+		created, err = t.createAddress(address)
+		return err
+	})
+	if err != nil && !isValidationError(err) {
+		created = &Address{Error: lobError(err)}
 	}
-	if len(address.AddressLine1) > 200 {
-		message = "address_line1 length must be less than or equal to 200 characters long"
-		status = 422
-		address.Error = &Error{
-			Message:    message,
-			StatusCode: status,
-		}
-		return address, Non200Error
+	return created, err
+}
+
+func (t *FakeLob) createAddress(address *Address) (*Address, error) {
+	if err := address.Validate(); err != nil {
+		return &Address{Error: validationError(err)}, err
 	}
-	if address.ID == "" {
-		address.ID = uuid.New()
+	stored := copyAddress(address)
+	if stored.ID == "" {
+		stored.ID = "adr_" + newFakeID()
 	}
-	t.addresses[address.ID] = address
-	return address, nil
+	stored.Error = nil
+	stored.Object = "address"
+	if stored.DateCreated.IsZero() {
+		stored.DateCreated = t.Now()
+		stored.DateModified = stored.DateCreated
+	}
+	if stored.AddressCountry == nil {
+		country := "US"
+		stored.AddressCountry = &country
+	}
+	t.store(stored.ID)
+	t.addresses[stored.ID] = stored
+	return copyAddress(stored), nil
 }
 
-func (t *fakeLob) GetAddress(id string) (*Address, error) {
+func (t *FakeLob) GetAddress(id string) (*Address, error) {
+	var address *Address
+	err := t.call("GetAddress", func() (err error) {
+		address, err = t.getAddress(id)
+		return err
+	})
+	if err != nil {
+		address = &Address{Error: lobError(err)}
+	}
+	return address, err
+}
+
+func (t *FakeLob) getAddress(id string) (*Address, error) {
 	address, ok := t.addresses[id]
 	if !ok {
-		return nil, errors.New("address not found")
+		return nil, newFakeError("addresses/"+id, http.StatusNotFound, "address not found")
 	}
-	return address, nil
+	return copyAddress(address), nil
 }
 
-func (t *fakeLob) DeleteAddress(id string) error {
-	delete(t.addresses, id)
-	return nil
+func (t *FakeLob) DeleteAddress(id string) error {
+	return t.call("DeleteAddress", func() error {
+		return t.deleteAddress(id)
+	})
 }
 
-func (t *fakeLob) ListAddresses(count int) (*ListAddressesResponse, error) {
-	if count <= 0 {
-		count = 10
+// deleteAddress deletes an address. Like Lob, it marks the address as deleted, so it can still be
+// retrieved but is no longer listed, used in checks or deleted again.
+func (t *FakeLob) deleteAddress(id string) error {
+	address, ok := t.addresses[id]
+	if !ok || isDeleted(address) {
+		return newFakeError("addresses/"+id, http.StatusNotFound, "address not found")
 	}
+	deleted := true
+	address.Deleted = &deleted
+	address.DateModified = t.Now()
+	return nil
+}
 
-	resp := new(ListAddressesResponse)
+// isDeleted reports whether the address has been deleted.
+func isDeleted(address *Address) bool {
+	return address.Deleted != nil && *address.Deleted
+}
 
-	data := make([]Address, len(t.addresses))
+func (t *FakeLob) ListAddresses(count int) (*ListAddressesResponse, error) {
+	var resp *ListAddressesResponse
+	err := t.call("ListAddresses", func() (err error) {
+		resp, err = t.listAddresses(listQuery{limit: count})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, err
+}
 
-	for _, address := range t.addresses {
-		data = append(data, *address)
+func (t *FakeLob) ListAddressesPage(pageURL string) (*ListAddressesResponse, error) {
+	var resp *ListAddressesResponse
+	err := t.call("ListAddresses", func() error {
+		q, err := parsePageURL("addresses", pageURL)
+		if err != nil {
+			return err
+		}
+		resp, err = t.listAddresses(q)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, err
+}
+
+func (t *FakeLob) listAddresses(q listQuery) (*ListAddressesResponse, error) {
+	objects := make([]listed, 0, len(t.addresses))
+	for id, address := range t.addresses {
+		if !isDeleted(address) {
+			objects = append(objects, listed{id: id, created: address.DateCreated})
+		}
+	}
+	ids, nextURL, previousURL, err := t.page("addresses", objects, q)
+	if err != nil {
+		return nil, err
 	}
 
-	resp.Data = data
-	resp.Count = count
+	resp := &ListAddressesResponse{
+		Data:        make([]Address, 0, len(ids)),
+		Object:      "list",
+		NextURL:     nextURL,
+		PreviousURL: previousURL,
+	}
+	for _, id := range ids {
+		resp.Data = append(resp.Data, *copyAddress(t.addresses[id]))
+	}
+	resp.Count = len(resp.Data)
 	return resp, nil
 }
 
-func (t *fakeLob) VerifyUSAddress(address *Address) (*USAddressVerificationResponse, error) {
-	resp := new(USAddressVerificationResponse)
-
-	if address != nil {
-		resp.Id = fmt.Sprintf("us_ver_%v", uuid.New())
-		resp.PrimaryLine = address.AddressLine1
-		resp.SecondaryLine = *address.AddressLine2
-		resp.LastLine = fmt.Sprintf("%v %v %v-%v", address.AddressCity, address.AddressState, address.AddressZip, "0000")
-		resp.Deliverability = "no_match"
-		for _, a := range t.addresses {
-			if a.AddressCity == address.AddressCity &&
-				a.AddressCountry == address.AddressCountry &&
-				a.AddressLine1 == address.AddressLine1 &&
-				a.AddressLine2 == address.AddressLine2 &&
-				a.AddressState == address.AddressState &&
-				a.AddressZip == address.AddressZip {
-				resp.Deliverability = "deliverable"
-				break
-			}
-		}
+func (t *FakeLob) VerifyUSAddress(address *Address) (*USAddressVerificationResponse, error) {
+	var resp *USAddressVerificationResponse
+	err := t.call("VerifyUSAddress", func() (err error) {
+		resp, err = t.verifyUSAddress(address)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return resp, err
+}
 
+// verifyUSAddress verifies addresses offline: an address is deliverable if it can be parsed into
+// USPS components.
+func (t *FakeLob) verifyUSAddress(address *Address) (*USAddressVerificationResponse, error) {
+	if address == nil || address.AddressLine1 == "" {
+		return nil, newFakeError("us_verifications", http.StatusUnprocessableEntity, "primary_line is required")
+	}
+	resp := &USAddressVerificationResponse{
+		Id:             "us_ver_" + newFakeID(),
+		PrimaryLine:    address.AddressLine1,
+		Deliverability: "undeliverable",
+		Object:         "us_verification",
+	}
+	if address.Name != nil {
+		resp.Recipient = *address.Name
+	}
+	if components, err := ParseUSAddress(address); err == nil {
+		resp.Deliverability = "deliverable"
+		resp.Components = *components
+		resp.PrimaryLine = components.PrimaryLine()
+		resp.SecondaryLine = components.SecondaryLine()
+		resp.LastLine = components.LastLine()
+		resp.DeliverabilityAnalysis.DpvConfirmation = "Y"
+	}
 	return resp, nil
 }
 
-func (t *fakeLob) GetStates() (*NamedObjectList, error) {
-	return &NamedObjectList{}, nil
+func (t *FakeLob) GetStates() (*NamedObjectList, error) {
+	var resp *NamedObjectList
+	err := t.call("GetStates", func() (err error) {
+		resp, err = t.getStates()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, err
+}
+
+func (t *FakeLob) getStates() (*NamedObjectList, error) {
+	return USStates(), nil
 }
 
-func (t *fakeLob) GetCountries() (*NamedObjectList, error) {
-	return &NamedObjectList{}, nil
+func (t *FakeLob) GetCountries() (*NamedObjectList, error) {
+	var resp *NamedObjectList
+	err := t.call("GetCountries", func() (err error) {
+		resp, err = t.getCountries()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, err
+}
+
+func (t *FakeLob) getCountries() (*NamedObjectList, error) {
+	return Countries(), nil
+}
+
+func (t *FakeLob) CreateBankAccount(request *CreateBankAccountRequest) (*BankAccount, error) {
+	var bankAccount *BankAccount
+	err := t.call("CreateBankAccount", func() (err error) {
+		bankAccount, err = t.createBankAccount(request)
+		return err
+	})
+	if err != nil && !isValidationError(err) {
+		bankAccount = &BankAccount{Error: lobError(err)}
+	}
+	return bankAccount, err
 }
 
-func (t *fakeLob) CreateBankAccount(request *CreateBankAccountRequest) (*BankAccount, error) {
+func (t *FakeLob) createBankAccount(request *CreateBankAccountRequest) (*BankAccount, error) {
+	if err := request.Validate(); err != nil {
+		return &BankAccount{Error: validationError(err)}, err
+	}
+	now := t.Now()
 	bankAccount := &BankAccount{
 		AccountNumber: request.AccountNumber,
+		AccountType:   request.AccountType,
 		BankName:      "Fake Bank",
-		DateCreated:   time.Now().Format("1/2/2006"),
-		DateModified:  time.Now().Format("1/2/2006"),
-		ID:            uuid.New(),
-		Metadata:      request.Metadata,
-		Object:        "",
+		DateCreated:   now,
+		DateModified:  now,
+		Description:   copyPointer(request.Description),
+		ID:            "bank_" + newFakeID(),
+		Metadata:      maps.Clone(request.Metadata),
+		Object:        "bank_account",
 		RoutingNumber: request.RoutingNumber,
 		Signatory:     request.Signatory,
 		Verified:      true,
 	}
-	t.bankAccounts[bankAccount.ID] = bankAccount
-	return bankAccount, nil
+	t.store(bankAccount.ID)
+	t.bankAccounts[bankAccount.ID] = copyBankAccount(bankAccount)
+	return copyBankAccount(bankAccount), nil
+}
+
+func (t *FakeLob) GetBankAccount(id string) (*BankAccount, error) {
+	var bankAccount *BankAccount
+	err := t.call("GetBankAccount", func() (err error) {
+		bankAccount, err = t.getBankAccount(id)
+		return err
+	})
+	if err != nil {
+		bankAccount = &BankAccount{Error: lobError(err)}
+	}
+	return bankAccount, err
 }
 
-func (t *fakeLob) GetBankAccount(id string) (*BankAccount, error) {
+func (t *FakeLob) getBankAccount(id string) (*BankAccount, error) {
 	bankAccount, ok := t.bankAccounts[id]
 	if !ok {
-		return nil, errors.New("bank account not found")
+		return nil, newFakeError("bank_accounts/"+id, http.StatusNotFound, "bank account not found")
 	}
-	return bankAccount, nil
+	return copyBankAccount(bankAccount), nil
 }
 
-func (t *fakeLob) ListBankAccounts(count int) (*ListBankAccountsResponse, error) {
-	if count <= 0 {
-		count = 10
+func (t *FakeLob) ListBankAccounts(count int) (*ListBankAccountsResponse, error) {
+	var resp *ListBankAccountsResponse
+	err := t.call("ListBankAccounts", func() (err error) {
+		resp, err = t.listBankAccounts(listQuery{limit: count})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return resp, err
+}
 
-	resp := new(ListBankAccountsResponse)
+func (t *FakeLob) ListBankAccountsPage(pageURL string) (*ListBankAccountsResponse, error) {
+	var resp *ListBankAccountsResponse
+	err := t.call("ListBankAccounts", func() error {
+		q, err := parsePageURL("bank_accounts", pageURL)
+		if err != nil {
+			return err
+		}
+		resp, err = t.listBankAccounts(q)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, err
+}
 
-	data := make([]BankAccount, len(t.bankAccounts))
+func (t *FakeLob) listBankAccounts(q listQuery) (*ListBankAccountsResponse, error) {
+	objects := make([]listed, 0, len(t.bankAccounts))
+	for id, bankAccount := range t.bankAccounts {
+		objects = append(objects, listed{id: id, created: bankAccount.DateCreated})
+	}
+	ids, nextURL, previousURL, err := t.page("bank_accounts", objects, q)
+	if err != nil {
+		return nil, err
+	}
 
+	resp := &ListBankAccountsResponse{
+		Data:        make([]BankAccount, 0, len(ids)),
+		Object:      "list",
+		NextURL:     nextURL,
+		PreviousURL: previousURL,
+	}
+	for _, id := range ids {
+		resp.Data = append(resp.Data, *copyBankAccount(t.bankAccounts[id]))
+	}
+	resp.Count = len(resp.Data)
+	return resp, nil
+}
+
+// FakeState is the objects stored in a FakeLob.
+type FakeState struct {
+	Checks       []Check
+	Addresses    []Address
+	BankAccounts []BankAccount
+}
+
+// Checks returns copies of the stored checks, oldest first.
+func (t *FakeLob) Checks() []Check {
+	return t.Snapshot().Checks
+}
+
+// Addresses returns copies of the stored addresses, oldest first.
+func (t *FakeLob) Addresses() []Address {
+	return t.Snapshot().Addresses
+}
+
+// BankAccounts returns copies of the stored bank accounts, oldest first.
+func (t *FakeLob) BankAccounts() []BankAccount {
+	return t.Snapshot().BankAccounts
+}
+
+// Reset removes every stored object.
+func (t *FakeLob) Reset() {
+	t.Restore(FakeState{})
+}
+
+// Seed stores the objects as if they had been created in Lob, keeping their IDs. Objects without
+// an ID are given one, and objects without a creation date are created now.
+func (t *FakeLob) Seed(state FakeState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seed(state)
+}
+
+func (t *FakeLob) seed(state FakeState) {
+	now := t.Now()
+	for i := range state.Addresses {
+		address := copyAddress(&state.Addresses[i])
+		if address.ID == "" {
+			address.ID = "adr_" + newFakeID()
+		}
+		if address.DateCreated.IsZero() {
+			address.DateCreated, address.DateModified = now, now
+		}
+		t.store(address.ID)
+		t.addresses[address.ID] = address
+	}
+	for i := range state.BankAccounts {
+		bankAccount := copyBankAccount(&state.BankAccounts[i])
+		if bankAccount.ID == "" {
+			bankAccount.ID = "bank_" + newFakeID()
+		}
+		if bankAccount.DateCreated.IsZero() {
+			bankAccount.DateCreated, bankAccount.DateModified = now, now
+		}
+		t.store(bankAccount.ID)
+		t.bankAccounts[bankAccount.ID] = bankAccount
+	}
+	for i := range state.Checks {
+		check := copyCheck(&state.Checks[i])
+		if check.ID == "" {
+			check.ID = "chk_" + newFakeID()
+		}
+		if check.DateCreated.IsZero() {
+			check.DateCreated, check.DateModified = now, now
+		}
+		t.store(check.ID)
+		t.checks[check.ID] = check
+	}
+}
+
+// Snapshot returns copies of every stored object, which Restore can return the fake to.
+func (t *FakeLob) Snapshot() FakeState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var state FakeState
+	for _, check := range t.checks {
+		state.Checks = append(state.Checks, *copyCheck(check))
+	}
+	for _, address := range t.addresses {
+		state.Addresses = append(state.Addresses, *copyAddress(address))
+	}
 	for _, bankAccount := range t.bankAccounts {
-		data = append(data, *bankAccount)
+		state.BankAccounts = append(state.BankAccounts, *copyBankAccount(bankAccount))
 	}
+	sort.Slice(state.Checks, func(i, j int) bool {
+		return t.createdBefore(state.Checks[i].DateCreated, state.Checks[i].ID, state.Checks[j].DateCreated, state.Checks[j].ID)
+	})
+	sort.Slice(state.Addresses, func(i, j int) bool {
+		return t.createdBefore(state.Addresses[i].DateCreated, state.Addresses[i].ID, state.Addresses[j].DateCreated, state.Addresses[j].ID)
+	})
+	sort.Slice(state.BankAccounts, func(i, j int) bool {
+		return t.createdBefore(state.BankAccounts[i].DateCreated, state.BankAccounts[i].ID, state.BankAccounts[j].DateCreated, state.BankAccounts[j].ID)
+	})
+	return state
+}
 
-	resp.Data = data
-	resp.Count = count
-	return resp, nil
+// Restore replaces every stored object with those in a snapshot.
+func (t *FakeLob) Restore(state FakeState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.created = make(map[string]int)
+	t.checks = make(map[string]*Check)
+	t.addresses = make(map[string]*Address)
+	t.bankAccounts = make(map[string]*BankAccount)
+	t.seed(state)
+}
+
+// store records that the object with the given ID was stored, to order objects created at the
+// same time.
+func (t *FakeLob) store(id string) {
+	if _, ok := t.created[id]; !ok {
+		t.sequence++
+		t.created[id] = t.sequence
+	}
+}
+
+// createdBefore orders objects by creation date, then the order they were stored in.
+func (t *FakeLob) createdBefore(a time.Time, aID string, b time.Time, bID string) bool {
+	if !a.Equal(b) {
+		return a.Before(b)
+	}
+	return t.created[aID] < t.created[bID]
+}
+
+// listed is a stored object to be listed.
+type listed struct {
+	id      string
+	created time.Time
+}
+
+// listQuery is the limit and cursors of a request for a page of a list.
+type listQuery struct {
+	limit  int
+	after  string
+	before string
+}
+
+// parsePageURL returns the query of a NextURL or PreviousURL of the resource's list. Like Lob,
+// the fake reads only the cursors and limit from it, so URLs from the lobtest simulator work too.
+func parsePageURL(resource, pageURL string) (listQuery, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil || !strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/"+resource) {
+		return listQuery{}, newFakeError(resource, http.StatusNotFound, "The requested resource does not exist.")
+	}
+	query := u.Query()
+	q := listQuery{after: query.Get("after"), before: query.Get("before")}
+	if s := query.Get("limit"); s != "" {
+		if q.limit, err = strconv.Atoi(s); err != nil {
+			return listQuery{}, newFakeError(resource, http.StatusUnprocessableEntity, "limit must be a number")
+		}
+	}
+	return q, nil
+}
+
+// page orders objects newest first, as Lob lists them, and returns the IDs of those on the page
+// the query asks for: the first limit of them, or those after or before a cursor. It also returns
+// the URLs of the next and previous pages, if there are any.
+func (t *FakeLob) page(resource string, objects []listed, q listQuery) (ids []string, nextURL, previousURL string, err error) {
+	limit := q.limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxListCount {
+		return nil, "", "", newFakeError(resource, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be less than or equal to %d", maxListCount))
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return t.createdBefore(objects[j].created, objects[j].id, objects[i].created, objects[i].id)
+	})
+	index := func(id string) int {
+		for i, o := range objects {
+			if o.id == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var start, end int
+	if q.before != "" {
+		if end = index(q.before); end < 0 {
+			end = 0
+		}
+		if start = end - limit; start < 0 {
+			start = 0
+		}
+	} else {
+		if q.after != "" {
+			start = index(q.after) + 1
+		}
+		if end = start + limit; end > len(objects) {
+			end = len(objects)
+		}
+	}
+	pageURL := func(cursor, id string) string {
+		return fmt.Sprintf("%s%s?%s=%s&limit=%d", BaseAPI, resource, cursor, id, limit)
+	}
+	if end < len(objects) && end > start {
+		nextURL = pageURL("after", objects[end-1].id)
+	}
+	if start > 0 && end > start {
+		previousURL = pageURL("before", objects[start].id)
+	}
+
+	ids = make([]string, 0, end-start)
+	for _, o := range objects[start:end] {
+		ids = append(ids, o.id)
+	}
+	return ids, nextURL, previousURL, nil
+}
+
+// newFakeID returns 16 random hex digits, the form of the IDs Lob generates.
+func newFakeID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// nextCheckNumber numbers checks in order, as Lob does for checks without a check number.
+func (t *FakeLob) nextCheckNumber() int {
+	t.checkNumber++
+	return t.checkNumber
+}
+
+// lobError returns the error Lob sent for a failed call, which the client decodes into the result
+// of the call, or nil if Lob sent none.
+func lobError(err error) *Error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	var body struct {
+		Error *Error `json:"error"`
+	}
+	json.Unmarshal(apiErr.Body, &body)
+	return body.Error
+}
+
+// isValidationError reports whether the client would have failed the call without making it.
+func isValidationError(err error) bool {
+	var errs ValidationErrors
+	return errors.As(err, &errs)
+}
+
+// newFakeError returns the error the client returns when Lob fails a call with the status.
+func newFakeError(endpoint string, status int, message string) error {
+	body, _ := json.Marshal(map[string]*Error{
+		"error": {Message: message, StatusCode: status},
+	})
+	return &APIError{StatusCode: status, URL: endpoint, Body: body}
+}
+
+// copyCheck copies a stored check along with everything it points to, so callers can't change
+// what the fake stores without locking it. Only the readers of uploaded files are shared.
+func copyCheck(check *Check) *Check {
+	c := *check
+	c.Error = copyPointer(check.Error)
+	c.Attachment = copyPointer(check.Attachment)
+	c.BankAccount = copyBankAccount(check.BankAccount)
+	c.CheckBottom = copyPointer(check.CheckBottom)
+	c.CheckBottomTemplateID = copyPointer(check.CheckBottomTemplateID)
+	c.Data = maps.Clone(check.Data)
+	c.From = copyAddress(check.From)
+	c.Logo = copyPointer(check.Logo)
+	c.MailType = copyPointer(check.MailType)
+	c.MergeVariables = copyMergeVariables(check.MergeVariables)
+	c.Message = copyPointer(check.Message)
+	c.Metadata = maps.Clone(check.Metadata)
+	c.Thumbnails = slices.Clone(check.Thumbnails)
+	c.To = copyAddress(check.To)
+	c.TrackingEvents = copyTrackingEvents(check.TrackingEvents)
+	if check.Tracking != nil {
+		tracking := *check.Tracking
+		tracking.Link = copyPointer(check.Tracking.Link)
+		tracking.Events = copyTrackingEvents(check.Tracking.Events)
+		c.Tracking = &tracking
+	}
+	return &c
+}
+
+func copyTrackingEvents(events []TrackingEvent) []TrackingEvent {
+	events = slices.Clone(events)
+	for i := range events {
+		events[i].Location = copyPointer(events[i].Location)
+	}
+	return events
+}
+
+func copyAddress(address *Address) *Address {
+	if address == nil {
+		return nil
+	}
+	a := *address
+	a.Error = copyPointer(address.Error)
+	a.AddressCity = copyPointer(address.AddressCity)
+	a.AddressCountry = copyPointer(address.AddressCountry)
+	a.AddressLine2 = copyPointer(address.AddressLine2)
+	a.AddressState = copyPointer(address.AddressState)
+	a.AddressZip = copyPointer(address.AddressZip)
+	a.Company = copyPointer(address.Company)
+	a.Deleted = copyPointer(address.Deleted)
+	a.Description = copyPointer(address.Description)
+	a.Email = copyPointer(address.Email)
+	a.Metadata = maps.Clone(address.Metadata)
+	a.Name = copyPointer(address.Name)
+	a.Phone = copyPointer(address.Phone)
+	return &a
+}
+
+func copyBankAccount(bankAccount *BankAccount) *BankAccount {
+	if bankAccount == nil {
+		return nil
+	}
+	b := *bankAccount
+	b.Error = copyPointer(bankAccount.Error)
+	b.Description = copyPointer(bankAccount.Description)
+	b.Metadata = maps.Clone(bankAccount.Metadata)
+	return &b
+}
+
+// copyPointer returns a pointer to a copy of what p points to, or nil.
+func copyPointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// copyMergeVariables copies merge variables along with the maps and slices nested in them.
+func copyMergeVariables(mergeVariables map[string]interface{}) map[string]interface{} {
+	if mergeVariables == nil {
+		return nil
+	}
+	return copyValue(mergeVariables).(map[string]interface{})
+}
+
+// copyValue copies the maps and slices a value decoded from JSON is made of.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			c[k] = copyValue(e)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = copyValue(e)
+		}
+		return c
+	}
+	return v
 }
@@ -1,7 +1,12 @@
 package lob
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/pborman/uuid"
 )
@@ -9,13 +14,17 @@ import (
 func TestFakeLobChecks(t *testing.T) {
 	lob := NewFakeLob()
 
+	name := "Big Bird"
 	city := "Davis"
-	country := "USA"
+	state := "CA"
+	country := "US"
 	address1 := "1234 Seasame St."
 	zip := "95616"
 	address := &Address{
 		ID:             uuid.New(),
+		Name:           &name,
 		AddressCity:    &city,
+		AddressState:   &state,
 		AddressCountry: &country,
 		AddressLine1:   address1,
 		AddressZip:     &zip,
@@ -27,7 +36,9 @@ func TestFakeLobChecks(t *testing.T) {
 
 	bankAccountRequest := &CreateBankAccountRequest{
 		AccountNumber: "1132234455",
-		RoutingNumber: "00000000",
+		RoutingNumber: "255077370",
+		Signatory:     "Big Bird",
+		AccountType:   AccountTypeCompany,
 	}
 	var bankAccount *BankAccount
 	if bankAccount, err = lob.CreateBankAccount(bankAccountRequest); err != nil {
@@ -36,9 +47,10 @@ func TestFakeLobChecks(t *testing.T) {
 
 	var check *Check
 	if check, err = lob.CreateCheck(&CreateCheckRequest{
-		Amount:        100,
+		Amount:        MustParseMoney("100.00"),
 		BankAccountID: bankAccount.ID,
-		ToAddressID:   address.ID,
+		From:          AddressID(address.ID),
+		To:            AddressID(address.ID),
 	}); err != nil {
 		t.Error("create check had an error")
 	}
@@ -52,3 +64,383 @@ func TestFakeLobChecks(t *testing.T) {
 		t.Errorf("expected check amount to be %v, got %v", check.Amount, retrievedCheck.Amount)
 	}
 }
+
+func TestFakeLobInlineAddress(t *testing.T) {
+	lob := NewFakeLob()
+
+	bankAccount, err := lob.CreateBankAccount(&CreateBankAccountRequest{
+		AccountNumber: "1132234455",
+		RoutingNumber: "255077370",
+		Signatory:     "Big Bird",
+		AccountType:   AccountTypeCompany,
+	})
+	if err != nil {
+		t.Fatal("create bank account had an error")
+	}
+
+	check, err := lob.CreateCheck(&CreateCheckRequest{
+		Amount:        MustParseMoney("100.00"),
+		BankAccountID: bankAccount.ID,
+		From:          InlineAddress(testAddress),
+		To: InlineAddress(&Address{
+			Name:         nullString("Lobster Test"),
+			AddressLine1: "1005 W Burnside St",
+			AddressCity:  nullString("Portland"),
+			AddressState: nullString("OR"),
+			AddressZip:   nullString("97209"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("create check had an error: %s", err)
+	}
+	if check.To == nil || check.To.ID == "" {
+		t.Fatal("expected the inline address to be stored")
+	}
+	if _, err := lob.GetAddress(check.To.ID); err != nil {
+		t.Errorf("get address had an error: %s", err)
+	}
+}
+
+var (
+	_ Lob   = (*FakeLob)(nil)
+	_ Pager = (*FakeLob)(nil)
+)
+
+func TestFakeLobConcurrent(t *testing.T) {
+	fake := NewFakeLob()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			address := *testAddress
+			address.ID = ""
+			created, err := fake.CreateAddress(&address)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := fake.GetAddress(created.ID); err != nil {
+				t.Error(err)
+			}
+			fake.ListAddresses(10)
+			fake.Addresses()
+		}()
+	}
+	wg.Wait()
+	if addresses := fake.Addresses(); len(addresses) != 10 {
+		t.Errorf("Expected 10 addresses, got %d", len(addresses))
+	}
+}
+
+func TestFakeLobDeepCopies(t *testing.T) {
+	fake := NewFakeLob()
+	fake.Seed(FakeState{
+		Addresses:    []Address{{ID: "adr_seeded", AddressLine1: "1005 W Burnside St", Name: nullString("Lobster Test"), Metadata: map[string]string{"k": "v"}}},
+		BankAccounts: []BankAccount{{ID: "bank_seeded", RoutingNumber: "255077370"}},
+		Checks: []Check{{
+			ID:         "chk_seeded",
+			Metadata:   map[string]string{"k": "v"},
+			Thumbnails: []Thumbnail{{Large: "large.png"}},
+		}},
+	})
+	req := &CreateCheckRequest{
+		Amount:         MustParseMoney("1"),
+		BankAccountID:  "bank_seeded",
+		From:           AddressID("adr_seeded"),
+		To:             AddressID("adr_seeded"),
+		MergeVariables: map[string]interface{}{"name": "Harry", "visits": []interface{}{"2019-06-01"}},
+		Message:        nullString("Thanks"),
+	}
+	created, err := fake.CreateCheck(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.MergeVariables["name"] = "changed"
+	req.MergeVariables["visits"].([]interface{})[0] = "changed"
+	*req.Message = "changed"
+	created.To.Metadata["k"] = "changed"
+	*created.To.Name = "changed"
+	*created.MailType = "changed"
+	if check, _ := fake.GetCheck(created.ID); check.MergeVariables["name"] != "Harry" || check.MergeVariables["visits"].([]interface{})[0] != "2019-06-01" {
+		t.Errorf("Expected the check to keep its own merge variables, got %+v", check.MergeVariables)
+	} else if *check.Message != "Thanks" || *check.MailType != MailTypeUspsFirstClass || *check.To.Name != "Lobster Test" {
+		t.Errorf("Expected the check to keep its own message, mail type and address, got %q, %q and %q", *check.Message, *check.MailType, *check.To.Name)
+	}
+	if address, _ := fake.GetAddress("adr_seeded"); address.Metadata["k"] != "v" || *address.Name != "Lobster Test" {
+		t.Errorf("Expected the address to keep its own metadata and name, got %+v", address)
+	}
+
+	address := &Address{
+		Name:         nullString("Lobster Test"),
+		AddressLine1: "1005 W Burnside St",
+		AddressCity:  nullString("Portland"),
+		AddressState: nullString("OR"),
+		AddressZip:   nullString("97209"),
+	}
+	createdAddress, err := fake.CreateAddress(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*address.Name = "changed"
+	*createdAddress.AddressCity = "changed"
+	if got, _ := fake.GetAddress(createdAddress.ID); *got.Name != "Lobster Test" || *got.AddressCity != "Portland" {
+		t.Errorf("Expected the created address to keep its own fields, got %q and %q", *got.Name, *got.AddressCity)
+	}
+
+	bankAccountReq := &CreateBankAccountRequest{
+		RoutingNumber: "255077370",
+		AccountNumber: "1234",
+		Signatory:     "Lobster Test",
+		AccountType:   AccountTypeCompany,
+		Metadata:      map[string]string{"k": "v"},
+	}
+	bankAccount, err := fake.CreateBankAccount(bankAccountReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bankAccountReq.Metadata["k"] = "changed"
+	bankAccount.Metadata["k"] = "changed"
+	if got, _ := fake.GetBankAccount(bankAccount.ID); got.Metadata["k"] != "v" {
+		t.Errorf("Expected the bank account to keep its own metadata, got %+v", got.Metadata)
+	}
+
+	check, err := fake.GetCheck("chk_seeded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			check.Metadata["k"] = strconv.Itoa(i)
+			check.Thumbnails[0].Large = strconv.Itoa(i)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		fake.Snapshot()
+	}
+	wg.Wait()
+	if got, _ := fake.GetCheck("chk_seeded"); got.Metadata["k"] != "v" || got.Thumbnails[0].Large != "large.png" {
+		t.Errorf("Expected the stored check to be unchanged, got %+v and %+v", got.Metadata, got.Thumbnails)
+	}
+}
+
+func TestFakeLobSnapshot(t *testing.T) {
+	fake := NewFakeLob()
+	fake.Seed(FakeState{
+		Addresses:    []Address{{ID: "adr_seeded", AddressLine1: "1005 W Burnside St"}},
+		BankAccounts: []BankAccount{{ID: "bank_seeded", RoutingNumber: "255077370"}},
+	})
+	check, err := fake.CreateCheck(&CreateCheckRequest{
+		Amount:        MustParseMoney("1"),
+		BankAccountID: "bank_seeded",
+		From:          AddressID("adr_seeded"),
+		To:            AddressID("adr_seeded"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check.To.AddressLine1 = "changed"
+	if address, _ := fake.GetAddress("adr_seeded"); address.AddressLine1 != "1005 W Burnside St" {
+		t.Errorf("Expected returned objects to be copies, got %q", address.AddressLine1)
+	}
+
+	snapshot := fake.Snapshot()
+	fake.Reset()
+	if len(fake.Checks()) != 0 || len(fake.Addresses()) != 0 || len(fake.BankAccounts()) != 0 {
+		t.Errorf("Expected nothing after a reset, got %+v", fake.Snapshot())
+	}
+	fake.Restore(snapshot)
+	checks := fake.Checks()
+	if len(checks) != 1 || checks[0].ID != check.ID || checks[0].BankAccount.ID != "bank_seeded" {
+		t.Errorf("Expected the check to be restored, got %+v", checks)
+	}
+	if _, err := fake.GetAddress("adr_seeded"); err != nil {
+		t.Errorf("Expected the address to be restored, got %v", err)
+	}
+}
+
+func TestFakeLobList(t *testing.T) {
+	fake := NewFakeLob()
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	fake.Now = func() time.Time { return now }
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		address := *testAddress
+		address.ID = ""
+		created, err := fake.CreateAddress(&address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	list, err := fake.ListAddresses(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Data) != 2 || list.Count != 2 || list.Data[0].ID != ids[2] || list.Data[1].ID != ids[1] {
+		t.Errorf("Expected the two newest addresses, newest first, got %+v", list.Data)
+	}
+	if expected := BaseAPI + "addresses?after=" + ids[1] + "&limit=2"; list.NextURL != expected || list.PreviousURL != "" {
+		t.Errorf("Expected the next page to be %s, got %q and %q", expected, list.NextURL, list.PreviousURL)
+	}
+
+	last, err := fake.ListAddressesPage(list.NextURL)
+	if err != nil || last.Count != 1 || last.Data[0].ID != ids[0] || last.NextURL != "" {
+		t.Errorf("Expected the oldest address on the next page, got %+v, %v", last, err)
+	}
+	if expected := BaseAPI + "addresses?before=" + ids[0] + "&limit=2"; last.PreviousURL != expected {
+		t.Errorf("Expected the previous page to be %s, got %q", expected, last.PreviousURL)
+	}
+	previous, err := fake.ListAddressesPage(last.PreviousURL)
+	if err != nil || previous.Count != 2 || previous.Data[0].ID != ids[2] || previous.PreviousURL != "" {
+		t.Errorf("Expected the first page again, got %+v, %v", previous, err)
+	}
+	if _, err := fake.ListAddressesPage(BaseAPI + "checks?after=" + ids[1]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a 404 for another resource's page, got %v", err)
+	}
+
+	list, err = fake.ListAddresses(0)
+	if err != nil || list.Count != 3 || list.NextURL != "" {
+		t.Errorf("Expected every address on one page, got %+v, %v", list, err)
+	}
+
+	checks, err := fake.ListChecks(10)
+	if err != nil || checks.Data == nil || checks.Count != 0 {
+		t.Errorf("Expected an empty list, got %+v, %v", checks, err)
+	}
+
+	var apiErr *APIError
+	if _, err := fake.ListBankAccounts(101); !errors.As(err, &apiErr) || apiErr.StatusCode != 422 {
+		t.Errorf("Expected a 422 for more than 100 bank accounts, got %v", err)
+	}
+}
+
+func TestFakeLobErrors(t *testing.T) {
+	fake := NewFakeLob()
+
+	check, err := fake.GetCheck("chk_missing")
+	if !errors.Is(err, ErrNotFound) || check == nil || check.Error == nil || check.Error.StatusCode != 404 {
+		t.Errorf("Expected a 404 like Lob's, got %+v, %v", check, err)
+	}
+	if _, err := fake.CancelCheck("chk_missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected cancelling a missing check to be a 404, got %v", err)
+	}
+	if err := fake.DeleteAddress("adr_missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected deleting a missing address to be a 404, got %v", err)
+	}
+	if bankAccount, err := fake.GetBankAccount("bank_missing"); !errors.Is(err, ErrNotFound) || bankAccount == nil || bankAccount.Error == nil || bankAccount.Error.StatusCode != 404 {
+		t.Errorf("Expected a 404 like Lob's, got %+v, %v", bankAccount, err)
+	}
+
+	fake.Faults = NewFaults()
+	fake.Faults.FailNext("GetAddress", 1, Fault{StatusCode: 500, Message: "Internal error"})
+	if address, err := fake.GetAddress("adr_missing"); err == nil || address.Error == nil || address.Error.Message != "Internal error" {
+		t.Errorf("Expected the fault on the address, got %+v, %v", address, err)
+	}
+	fake.Faults.FailNext("CreateBankAccount", 1, Fault{Timeout: true})
+	bankAccount, err := fake.CreateBankAccount(&CreateBankAccountRequest{
+		AccountNumber: "1132234455",
+		RoutingNumber: "255077370",
+		Signatory:     "Big Bird",
+		AccountType:   AccountTypeCompany,
+	})
+	if err == nil || bankAccount == nil || bankAccount.ID != "" || bankAccount.Error != nil {
+		t.Errorf("Expected a lost response to return an empty bank account like the client, got %+v, %v", bankAccount, err)
+	}
+}
+
+func TestFakeLobCancelledChecks(t *testing.T) {
+	fake := NewFakeLob()
+	fake.Seed(FakeState{
+		Addresses:    []Address{{ID: "adr_seeded", AddressLine1: "1005 W Burnside St"}},
+		BankAccounts: []BankAccount{{ID: "bank_seeded", RoutingNumber: "255077370"}},
+	})
+	check, err := fake.CreateCheck(&CreateCheckRequest{
+		Amount:        MustParseMoney("1"),
+		BankAccountID: "bank_seeded",
+		From:          AddressID("adr_seeded"),
+		To:            AddressID("adr_seeded"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := fake.CancelCheck(check.ID); err != nil || !resp.Deleted {
+		t.Fatalf("Expected the check to be cancelled, got %+v, %v", resp, err)
+	}
+	if cancelled, err := fake.GetCheck(check.ID); err != nil || !cancelled.Deleted {
+		t.Errorf("Expected the cancelled check to be marked deleted, got %+v, %v", cancelled, err)
+	}
+	if list, _ := fake.ListChecks(10); list.Count != 0 {
+		t.Errorf("Expected cancelled checks not to be listed, got %+v", list.Data)
+	}
+	if _, err := fake.CancelCheck(check.ID); !errors.Is(err, ErrCheckNotCancellable) {
+		t.Errorf("Expected a cancelled check not to be cancellable again, got %v", err)
+	}
+}
+
+func TestFakeLobDeletedAddresses(t *testing.T) {
+	fake := NewFakeLob()
+	fake.Seed(FakeState{
+		Addresses:    []Address{{ID: "adr_seeded", AddressLine1: "1005 W Burnside St"}},
+		BankAccounts: []BankAccount{{ID: "bank_seeded", RoutingNumber: "255077370"}},
+	})
+	if err := fake.DeleteAddress("adr_seeded"); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := fake.GetAddress("adr_seeded"); err != nil || deleted.Deleted == nil || !*deleted.Deleted {
+		t.Errorf("Expected the deleted address to be marked deleted, got %+v, %v", deleted, err)
+	}
+	if list, _ := fake.ListAddresses(10); list.Count != 0 {
+		t.Errorf("Expected deleted addresses not to be listed, got %+v", list.Data)
+	}
+	if err := fake.DeleteAddress("adr_seeded"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected deleting an address again to be a 404, got %v", err)
+	}
+	_, err := fake.CreateCheck(&CreateCheckRequest{
+		Amount:        MustParseMoney("1"),
+		BankAccountID: "bank_seeded",
+		From:          AddressID("adr_seeded"),
+		To:            AddressID("adr_seeded"),
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected a check to a deleted address to be a 422, got %v", err)
+	}
+}
+
+func TestFakeLobVerifyAndFixtures(t *testing.T) {
+	fake := NewFakeLob()
+
+	resp, err := fake.VerifyUSAddress(&Address{
+		AddressLine1: "1005 W Burnside St",
+		AddressCity:  nullString("Portland"),
+		AddressState: nullString("OR"),
+		AddressZip:   nullString("97209"),
+	})
+	if err != nil || resp.Deliverability != "deliverable" || resp.SecondaryLine != "" {
+		t.Errorf("Expected an address without a second line to be deliverable, got %+v, %v", resp, err)
+	}
+	if resp, err := fake.VerifyUSAddress(nil); err == nil || resp != nil {
+		t.Errorf("Expected verifying no address to fail, got %+v", resp)
+	}
+
+	states, err := fake.GetStates()
+	if err != nil || len(states.Data) < 50 {
+		t.Fatalf("Expected the US states, got %+v, %v", states, err)
+	}
+	found := false
+	for _, state := range states.Data {
+		found = found || (state.ShortName == "OR" && state.Name == "Oregon")
+	}
+	if !found {
+		t.Error("Expected Oregon among the states")
+	}
+	if countries, err := fake.GetCountries(); err != nil || len(countries.Data) < 200 {
+		t.Errorf("Expected the countries, got %+v, %v", countries, err)
+	}
+}
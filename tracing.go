@@ -0,0 +1,109 @@
+package lob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Tracer starts a span for every request the client makes, as a child of any span in the
+// request's context. Implementations must be safe for concurrent use.
+type Tracer interface {
+	// Start starts a span with the given name.
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Inject adds the trace context of ctx to the headers of the outgoing request.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	SetAttributes(attributes ...Field)
+	// End ends the span, marking it as failed if err is not nil.
+	End(err error)
+}
+
+// Span attributes set by the client. The resource ID attribute is named after the resource,
+// e.g. "check.id" or "address.id".
+const (
+	AttributeHTTPMethod     = "http.request.method"
+	AttributeHTTPRoute      = "http.route"
+	AttributeHTTPStatusCode = "http.response.status_code"
+	AttributeLobRequestID   = "lob.request_id"
+)
+
+// resourceNames are the singular names of Lob's resources, by endpoint.
+var resourceNames = map[string]string{
+	"addresses":        "address",
+	"bank_accounts":    "bank_account",
+	"checks":           "check",
+	"us_verifications": "us_verification",
+}
+
+// WithContext returns a copy of the client whose requests are made with ctx, so that they are
+// cancelled with it and traced as part of any span it carries. The copy is scoped to the one
+// operation ctx belongs to: make it where the calls are made, as in
+// l.WithContext(ctx).GetCheck(id), and do not keep it, since its calls fail once ctx is done.
+func (l *lob) WithContext(ctx context.Context) *lob {
+	c := *l
+	c.ctx = ctx
+	return &c
+}
+
+// context returns the context requests are made with.
+func (l *lob) context() context.Context {
+	if l.ctx == nil {
+		return context.Background()
+	}
+	return l.ctx
+}
+
+// noopSpan is the span of a client without a Tracer.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Field) {}
+func (noopSpan) End(error)              {}
+
+// startSpan starts the span for a request.
+func (l *lob) startSpan(ctx context.Context, method, endpoint string) (context.Context, Span) {
+	if l.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	route := normalizeEndpoint(endpoint)
+	ctx, span := l.Tracer.Start(ctx, "lob "+method+" "+route)
+	span.SetAttributes(
+		Field{Key: AttributeHTTPMethod, Value: method},
+		Field{Key: AttributeHTTPRoute, Value: route},
+	)
+	return ctx, span
+}
+
+// endSpan records the outcome of a request on its span and ends it. The resource ID is taken
+// from the endpoint, or from the response for requests that create a resource.
+func endSpan(span Span, endpoint string, status int, requestID string, data []byte, err error) {
+	if status != 0 {
+		span.SetAttributes(Field{Key: AttributeHTTPStatusCode, Value: status})
+	}
+	if requestID != "" {
+		span.SetAttributes(Field{Key: AttributeLobRequestID, Value: requestID})
+	}
+
+	segments := strings.Split(strings.Trim(strings.SplitN(endpoint, "?", 2)[0], "/"), "/")
+	if name, ok := resourceNames[segments[0]]; ok {
+		id := ""
+		if len(segments) > 1 {
+			id = segments[1]
+		} else if status == 200 {
+			var resource struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(data, &resource) == nil {
+				id = resource.ID
+			}
+		}
+		if id != "" {
+			span.SetAttributes(Field{Key: name + ".id", Value: id})
+		}
+	}
+	span.End(err)
+}
@@ -0,0 +1,134 @@
+package lob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recordingTracer keeps the attributes and error of every span it starts.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name       string
+	ctx        context.Context
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+type spanKey struct{}
+
+func (r *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	span := &recordingSpan{name: name, ctx: ctx, attributes: make(map[string]interface{})}
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, spanKey{}, name), span
+}
+
+func (r *recordingTracer) Inject(ctx context.Context, header http.Header) {
+	header.Set("X-Test-Span", ctx.Value(spanKey{}).(string))
+}
+
+func (s *recordingSpan) SetAttributes(attributes ...Field) {
+	for _, a := range attributes {
+		s.attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) End(err error) {
+	s.err = err
+	s.ended = true
+}
+
+type testContextKey struct{}
+
+func TestTracing(t *testing.T) {
+	var injected string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		injected = r.Header.Get("X-Test-Span")
+		w.Header().Set("X-Request-Id", "req_123")
+		w.Write([]byte(`{"id": "adr_new"}`))
+	}))
+	defer server.Close()
+
+	tracer := new(recordingTracer)
+	l := NewLob(server.URL+"/", "test_key", testUserAgent)
+	l.Tracer = tracer
+	ctx := context.WithValue(context.Background(), testContextKey{}, "caller")
+
+	if _, err := l.WithContext(ctx).GetAddress("adr_123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.CreateAddress(testAddress); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.spans))
+	}
+	get, create := tracer.spans[0], tracer.spans[1]
+	if get.name != "lob GET addresses/:id" || get.ctx.Value(testContextKey{}) != "caller" {
+		t.Errorf("Expected a span started from the caller's context, got %s", get.name)
+	}
+	if injected != "lob POST addresses" {
+		t.Errorf("Expected the span to be injected into the request, got %q", injected)
+	}
+	expected := map[string]interface{}{
+		AttributeHTTPMethod:     "GET",
+		AttributeHTTPRoute:      "addresses/:id",
+		AttributeHTTPStatusCode: 200,
+		AttributeLobRequestID:   "req_123",
+		"address.id":            "adr_123",
+	}
+	for k, v := range expected {
+		if get.attributes[k] != v {
+			t.Errorf("Expected %s = %v, got %v", k, v, get.attributes[k])
+		}
+	}
+	if create.attributes["address.id"] != "adr_new" || !create.ended || create.err != nil {
+		t.Errorf("Expected the created address's ID on an ended span, got %+v", create)
+	}
+}
+
+func TestTracingEncodingErrors(t *testing.T) {
+	tracer := new(recordingTracer)
+	l := NewLob("http://localhost/", "test_key", testUserAgent).WithAPIVersion(APIVersion20200211)
+	l.Tracer = tracer
+	_, err := l.CreateCheck(&CreateCheckRequest{
+		Amount:         MustParseMoney("1"),
+		BankAccountID:  "bank_123",
+		From:           AddressID("adr_123"),
+		To:             AddressID("adr_456"),
+		MergeVariables: map[string]interface{}{"callback": func() {}},
+	})
+	var encodingErr *json.UnsupportedTypeError
+	if !errors.As(err, &encodingErr) {
+		t.Fatalf("Expected a request body that can't be encoded to fail, got %v", err)
+	}
+	if len(tracer.spans) != 1 || !tracer.spans[0].ended || tracer.spans[0].err != err {
+		t.Errorf("Expected the failure to end a span, got %+v", tracer.spans)
+	}
+}
+
+func TestWithContextCancels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l := NewLob(server.URL+"/", "test_key", testUserAgent)
+	if _, err := l.WithContext(ctx).GetAddress("adr_123"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the request to be cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package lob
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	usZipPattern         = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+	countryCodePattern   = regexp.MustCompile(`^[A-Z]{2}$`)
+	routingNumberPattern = regexp.MustCompile(`^\d{9}$`)
+	accountNumberPattern = regexp.MustCompile(`^\d{1,17}$`)
+)
+
+// FieldError describes a request field that Lob would reject.
+type FieldError struct {
+	// Field is the name of the field in Lob's API, e.g. "memo" or "to[address_zip]".
+	Field   string
+	Message string
+	// Err is the underlying error, such as ErrCheckAmountTooLarge, if there is one.
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors lists every invalid field found in a request. Requests that fail validation
+// are not sent to Lob; the client returns a ValidationErrors instead.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap lets errors.Is and errors.As match the underlying field errors.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// Field returns the error for the named field, if there is one.
+func (e ValidationErrors) Field(name string) (FieldError, bool) {
+	for _, fieldErr := range e {
+		if fieldErr.Field == name {
+			return fieldErr, true
+		}
+	}
+	return FieldError{}, false
+}
+
+// validationStatusCode is the status code Lob uses for invalid requests.
+const validationStatusCode = 422
+
+// validationError describes a failed validation the way Lob describes a rejected request, so
+// callers that inspect the Error field of a response see the same thing either way.
+func validationError(err error) *Error {
+	return &Error{
+		Message:    err.Error(),
+		StatusCode: validationStatusCode,
+	}
+}
+
+// validator collects field errors while a request is checked.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) wrap(field string, err error, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...), Err: err})
+}
+
+func (v *validator) required(field string, present bool) {
+	if !present {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) maxLength(field string, s *string, max int) {
+	if s != nil && utf8.RuneCountInString(*s) > max {
+		v.add(field, "length must be less than or equal to %d characters long", max)
+	}
+}
+
+func (v *validator) oneOf(field string, s *string, allowed ...string) {
+	if s == nil {
+		return
+	}
+	for _, a := range allowed {
+		if *s == a {
+			return
+		}
+	}
+	v.add(field, "must be one of [%s]", strings.Join(allowed, ", "))
+}
+
+// file checks that exactly one kind of source is set for f, reporting whether it is usable.
+func (v *validator) file(field string, f *File) bool {
+	sources := 0
+	for _, set := range []bool{f.URL != "", f.HTML != "", f.IsUpload()} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		v.add(field, "must have exactly one of a URL, HTML or file contents")
+		return false
+	}
+	if f.URL != "" {
+		u, err := url.Parse(f.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.add(field, "must be an http or https URL")
+			return false
+		}
+	}
+	return true
+}
+
+// imageFile checks that f is a URL or upload of a PNG or JPEG image.
+func (v *validator) imageFile(field string, f *File) {
+	if f == nil || !v.file(field, f) {
+		return
+	}
+	name := f.Name
+	if f.URL != "" {
+		u, _ := url.Parse(f.URL)
+		name = u.Path
+	}
+	switch {
+	case f.HTML != "":
+		v.add(field, "must be a URL or an image file")
+	case !hasExtension(name, ".png", ".jpg", ".jpeg"):
+		v.add(field, "must be a PNG or JPEG image")
+	}
+}
+
+// documentFile checks that f is a URL, an HTML document or an upload of a PDF.
+func (v *validator) documentFile(field string, f *File) {
+	if f == nil || !v.file(field, f) {
+		return
+	}
+	if f.IsUpload() && !hasExtension(f.Name, ".pdf") {
+		v.add(field, "must be a PDF file")
+	}
+}
+
+func hasExtension(name string, extensions ...string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) matches(field string, s *string, pattern *regexp.Regexp, format string) {
+	if s != nil && !pattern.MatchString(*s) {
+		v.add(field, "must be %s", format)
+	}
+}
+
+// validRoutingNumber reports whether s is a nine digit ABA routing number with a valid checksum.
+func validRoutingNumber(s string) bool {
+	if !routingNumberPattern.MatchString(s) {
+		return false
+	}
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, c := range s {
+		sum += weights[i%3] * int(c-'0')
+	}
+	return sum%10 == 0
+}
+
+func nonEmpty(s *string) bool {
+	return s != nil && strings.TrimSpace(*s) != ""
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
@@ -0,0 +1,137 @@
+package lob
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func expectFieldErrors(t *testing.T, name string, err error, fields []string) {
+	if len(fields) == 0 {
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", name, err)
+		}
+		return
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Errorf("%s: expected ValidationErrors, got %v", name, err)
+		return
+	}
+	if len(errs) != len(fields) {
+		t.Errorf("%s: expected errors for %v, got %s", name, fields, errs)
+	}
+	for _, field := range fields {
+		if _, ok := errs.Field(field); !ok {
+			t.Errorf("%s: expected an error for %s, got %s", name, field, errs)
+		}
+	}
+}
+
+func TestAddressValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Address)
+		fields []string
+	}{
+		{"valid", func(*Address) {}, nil},
+		{"company instead of name", func(a *Address) {
+			a.Name = nil
+			a.Company = nullString("Powell's Books")
+		}, nil},
+		{"ZIP+4", func(a *Address) { a.AddressZip = nullString("97209-2844") }, nil},
+		{"no name or company", func(a *Address) { a.Name = nil }, []string{"name"}},
+		{"long name", func(a *Address) { a.Name = nullString(strings.Repeat("n", 41)) }, []string{"name"}},
+		{"long address line", func(a *Address) { a.AddressLine1 = strings.Repeat("1", 201) }, []string{"address_line1"}},
+		{"missing US fields", func(a *Address) {
+			a.AddressLine1 = ""
+			a.AddressCity = nil
+			a.AddressState = nil
+			a.AddressZip = nil
+		}, []string{"address_line1", "address_city", "address_state", "address_zip"}},
+		{"lower case state", func(a *Address) { a.AddressState = nullString("or") }, nil},
+		{"state name", func(a *Address) { a.AddressState = nullString("Oregon") }, []string{"address_state"}},
+		{"bad ZIP", func(a *Address) { a.AddressZip = nullString("9720") }, []string{"address_zip"}},
+		{"bad country", func(a *Address) { a.AddressCountry = nullString("USA") }, []string{"address_country"}},
+		{"international", func(a *Address) {
+			a.AddressCountry = nullString("CA")
+			a.AddressState = nullString("BC")
+			a.AddressZip = nullString("V6B 1A1")
+		}, nil},
+		{"international without city or state", func(a *Address) {
+			a.AddressCountry = nullString("GB")
+			a.AddressCity = nil
+			a.AddressState = nil
+		}, nil},
+		{"international without address line", func(a *Address) {
+			a.AddressCountry = nullString("GB")
+			a.AddressLine1 = ""
+		}, []string{"address_line1"}},
+	}
+
+	for _, test := range tests {
+		address := *testAddress
+		test.modify(&address)
+		expectFieldErrors(t, test.name, address.Validate(), test.fields)
+	}
+}
+
+func TestCreateBankAccountRequestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*CreateBankAccountRequest)
+		fields []string
+	}{
+		{"valid", func(*CreateBankAccountRequest) {}, nil},
+		{"missing fields", func(r *CreateBankAccountRequest) { *r = CreateBankAccountRequest{} },
+			[]string{"routing_number", "account_number", "signatory", "account_type"}},
+		{"short routing number", func(r *CreateBankAccountRequest) { r.RoutingNumber = "25507737" }, []string{"routing_number"}},
+		{"bad routing checksum", func(r *CreateBankAccountRequest) { r.RoutingNumber = "255077371" }, []string{"routing_number"}},
+		{"letters in account number", func(r *CreateBankAccountRequest) { r.AccountNumber = "12ab" }, []string{"account_number"}},
+		{"long account number", func(r *CreateBankAccountRequest) { r.AccountNumber = strings.Repeat("1", 18) }, []string{"account_number"}},
+		{"long signatory", func(r *CreateBankAccountRequest) { r.Signatory = strings.Repeat("s", 31) }, []string{"signatory"}},
+		{"unknown account type", func(r *CreateBankAccountRequest) { r.AccountType = "savings" }, []string{"account_type"}},
+	}
+
+	for _, test := range tests {
+		req := &CreateBankAccountRequest{
+			RoutingNumber: "322271627",
+			AccountNumber: "123456789",
+			Signatory:     "Lobster Test",
+			AccountType:   AccountTypeIndividual,
+		}
+		test.modify(req)
+		expectFieldErrors(t, test.name, req.Validate(), test.fields)
+	}
+}
+
+func TestCreateCheckRequestValidatesInlineAddresses(t *testing.T) {
+	req := &CreateCheckRequest{
+		Amount:        MustParseMoney("1"),
+		BankAccountID: "bank_123",
+		From:          AddressID("adr_123"),
+		To:            InlineAddress(&Address{Name: nullString("Lobster Test"), AddressLine1: "1005 W Burnside St"}),
+	}
+	expectFieldErrors(t, "inline address", req.Validate(), []string{"to[address_city]", "to[address_state]", "to[address_zip]"})
+}
+
+func TestFakeLobValidation(t *testing.T) {
+	fake := NewFakeLob()
+
+	address, err := fake.CreateAddress(&Address{Name: nullString(strings.Repeat("n", 41)), AddressLine1: "1005 W Burnside St"})
+	expectFieldErrors(t, "fake address", err, []string{"name", "address_city", "address_state", "address_zip"})
+	if address.Error == nil || address.Error.StatusCode != 422 {
+		t.Errorf("Expected a 422 error on the address, got %+v", address.Error)
+	}
+
+	bankAccount, err := fake.CreateBankAccount(&CreateBankAccountRequest{
+		RoutingNumber: "00000000",
+		AccountNumber: "1234",
+		Signatory:     "Lobster Test",
+		AccountType:   AccountTypeCompany,
+	})
+	expectFieldErrors(t, "fake bank account", err, []string{"routing_number"})
+	if bankAccount.Error == nil || bankAccount.Error.StatusCode != 422 {
+		t.Errorf("Expected a 422 error on the bank account, got %+v", bankAccount.Error)
+	}
+}
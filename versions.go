@@ -0,0 +1,73 @@
+package lob
+
+import "fmt"
+
+// API versions whose changes this package accounts for. Versions are dates, and later versions
+// sort after earlier ones.
+const (
+	APIVersion20190601 = "2019-06-01"
+	// APIVersion20200211 renamed the data field of checks to merge_variables.
+	APIVersion20200211 = "2020-02-11"
+)
+
+// versionBefore reports whether version predates since.
+func versionBefore(version, since string) bool {
+	return version < since
+}
+
+// versionedRequest is a request whose fields are named differently in different API versions.
+type versionedRequest interface {
+	// forVersion returns the request as it should be sent to the given version.
+	forVersion(version string) interface{}
+}
+
+// versionedResponse is a response whose fields are named differently in different API versions.
+type versionedResponse interface {
+	// fromVersion fills in the fields the given version does not send, so callers see the same
+	// response whichever version they use.
+	fromVersion(version string)
+}
+
+// mergeVariablesForVersion returns a check's data and merge variables as the given API version
+// names them: as data, with values formatted as strings, before APIVersion20200211, and as
+// merge_variables from then on. The one the version does not use is nil.
+func mergeVariablesForVersion(version string, data map[string]string, mergeVariables map[string]interface{}) (map[string]string, map[string]interface{}) {
+	if versionBefore(version, APIVersion20200211) {
+		if data == nil && mergeVariables != nil {
+			data = make(map[string]string, len(mergeVariables))
+			for k, v := range mergeVariables {
+				data[k] = fmt.Sprint(v)
+			}
+		}
+		return data, nil
+	}
+	if mergeVariables == nil && data != nil {
+		mergeVariables = make(map[string]interface{}, len(data))
+		for k, v := range data {
+			mergeVariables[k] = v
+		}
+	}
+	return nil, mergeVariables
+}
+
+func (req *CreateCheckRequest) forVersion(version string) interface{} {
+	c := *req
+	c.Data, c.MergeVariables = mergeVariablesForVersion(version, c.Data, c.MergeVariables)
+	return &c
+}
+
+// fromVersion fills in whichever of data and merge_variables the version did not send.
+func (c *Check) fromVersion(version string) {
+	if c.Data == nil {
+		c.Data, _ = mergeVariablesForVersion(APIVersion20190601, nil, c.MergeVariables)
+	}
+	if c.MergeVariables == nil {
+		_, c.MergeVariables = mergeVariablesForVersion(APIVersion20200211, c.Data, nil)
+	}
+}
+
+func (resp *ListChecksResponse) fromVersion(version string) {
+	for i := range resp.Data {
+		resp.Data[i].fromVersion(version)
+	}
+}
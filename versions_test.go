@@ -0,0 +1,84 @@
+package lob
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAPIVersionPerCall(t *testing.T) {
+	var versions []string
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := r.Header.Get("Lob-Version")
+		versions = append(versions, version)
+		data, _ := ioutil.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		bodies = append(bodies, body)
+
+		if version == APIVersion20200211 {
+			w.Write([]byte(`{"id": "chk_new", "merge_variables": {"name": "Harry", "visits": 3}}`))
+		} else {
+			w.Write([]byte(`{"id": "chk_old", "data": {"name": "Harry"}}`))
+		}
+	}))
+	defer server.Close()
+
+	l := NewLob(server.URL+"/", "test_key", testUserAgent)
+	upgraded := l.WithAPIVersion(APIVersion20200211)
+	if l.APIVersion != APIVersion || upgraded.APIVersion != APIVersion20200211 {
+		t.Fatalf("Expected the original client to keep its version, got %s and %s", l.APIVersion, upgraded.APIVersion)
+	}
+
+	req := &CreateCheckRequest{
+		Amount:        MustParseMoney("10"),
+		BankAccountID: "bank_123",
+		From:          AddressID("adr_123"),
+		To:            AddressID("adr_456"),
+		Data:          map[string]string{"name": "Harry"},
+	}
+	old, err := l.CreateCheck(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer, err := upgraded.CreateCheck(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(versions, []string{APIVersion, APIVersion20200211}) {
+		t.Errorf("Expected each client to send its own version, got %v", versions)
+	}
+	if _, ok := bodies[0]["data"]; !ok || bodies[0]["merge_variables"] != nil {
+		t.Errorf("Expected data to be sent to %s, got %v", APIVersion, bodies[0])
+	}
+	if _, ok := bodies[1]["merge_variables"]; !ok || bodies[1]["data"] != nil {
+		t.Errorf("Expected merge_variables to be sent to %s, got %v", APIVersion20200211, bodies[1])
+	}
+	if req.MergeVariables != nil {
+		t.Error("Expected the caller's request to be left unchanged")
+	}
+
+	if old.Data["name"] != "Harry" || old.MergeVariables["name"] != "Harry" {
+		t.Errorf("Expected data to fill in merge variables, got %+v", old)
+	}
+	if newer.Data["name"] != "Harry" || newer.MergeVariables["visits"] != float64(3) {
+		t.Errorf("Expected merge variables to fill in data, got %+v", newer)
+	}
+}
+
+func TestCreateCheckRequestForVersion(t *testing.T) {
+	req := &CreateCheckRequest{MergeVariables: map[string]interface{}{"visits": 3}}
+	old := req.forVersion(APIVersion20190601).(*CreateCheckRequest)
+	if !reflect.DeepEqual(old.Data, map[string]string{"visits": "3"}) || old.MergeVariables != nil {
+		t.Errorf("Expected merge variables to be sent as data, got %+v", old)
+	}
+	newer := req.forVersion(APIVersion20200211).(*CreateCheckRequest)
+	if newer.Data != nil || !reflect.DeepEqual(newer.MergeVariables, req.MergeVariables) {
+		t.Errorf("Expected merge variables to be sent as is, got %+v", newer)
+	}
+}